@@ -0,0 +1,230 @@
+package validator
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+)
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+//将校验后的参数绑定到结构体，支持任意层级的嵌套结构体、匿名嵌入及指针字段
+//未打valid标签的结构体字段会递归绑定，标签为"-"的字段跳过
+func (v *Validator) ValuesToStruct(dst interface{}) error {
+	vl := reflect.ValueOf(dst)
+	if vl.Kind() != reflect.Ptr || vl.IsNil() || vl.Elem().Kind() != reflect.Struct {
+		return NewTextError("interface must be a pointer to struct")
+	}
+	_, err := v.bindStruct(vl.Elem(), "", map[reflect.Type]bool{})
+	return err
+}
+
+//绑定结构体的所有字段，返回是否有字段被赋值，visiting为正在绑定的结构体类型
+func (v *Validator) bindStruct(sv reflect.Value, path string, visiting map[reflect.Type]bool) (bool, error) {
+	st := sv.Type()
+	visiting[st] = true
+	defer delete(visiting, st)
+	bound := false
+	for i := 0; i < st.NumField(); i++ {
+		sf := st.Field(i)
+		if sf.PkgPath != "" && !sf.Anonymous {
+			continue
+		}
+		fv := sv.Field(i)
+		fieldPath := sf.Name
+		if path != "" {
+			fieldPath = path + "." + sf.Name
+		}
+		paramName, tagged := sf.Tag.Lookup(ValidTag)
+		if paramName == "-" {
+			continue
+		}
+		if !tagged || paramName == "" {
+			ok, err := v.bindNested(fv, fieldPath, visiting)
+			if err != nil {
+				return bound, err
+			}
+			bound = bound || ok
+			continue
+		}
+		if !fv.CanSet() {
+			return bound, NewTextError(fmt.Sprintf("field %s bound to param %s is not settable", fieldPath, paramName))
+		}
+		ok, err := v.bindField(fv, paramName, fieldPath)
+		if err != nil {
+			return bound, err
+		}
+		bound = bound || ok
+	}
+	return bound, nil
+}
+
+//递归绑定未打标签的结构体或结构体指针，指针为nil时只有内部字段被赋值才会分配
+//指向正在绑定的结构体类型的指针跳过，避免自引用的结构体无限递归
+func (v *Validator) bindNested(fv reflect.Value, path string, visiting map[reflect.Type]bool) (bool, error) {
+	t := fv.Type()
+	if t.Kind() == reflect.Ptr {
+		if t.Elem().Kind() != reflect.Struct || isTextUnmarshaler(t.Elem()) || visiting[t.Elem()] {
+			return false, nil
+		}
+		if !fv.IsNil() {
+			return v.bindStruct(fv.Elem(), path, visiting)
+		}
+		nv := reflect.New(t.Elem())
+		ok, err := v.bindStruct(nv.Elem(), path, visiting)
+		if !ok || err != nil {
+			return ok, err
+		}
+		if !fv.CanSet() {
+			return false, NewTextError(fmt.Sprintf("can not set embedded pointer %s to unexported struct", path))
+		}
+		fv.Set(nv)
+		return true, nil
+	}
+	if t.Kind() != reflect.Struct || isTextUnmarshaler(t) {
+		return false, nil
+	}
+	return v.bindStruct(fv, path, visiting)
+}
+
+//绑定单个参数，依次使用格式解析值、校验后的值和默认值
+func (v *Validator) bindField(fv reflect.Value, name, path string) (bool, error) {
+	if parsed, ok := v.parsedMap[name]; ok {
+		if target := assignTarget(fv, reflect.TypeOf(parsed)); target.IsValid() {
+			target.Set(reflect.ValueOf(parsed))
+			return true, nil
+		}
+	}
+	value, ok := v.valueMap[name]
+	if !ok && v.presenceMap[name] == PresenceAbsent {
+		value, ok = v.defaultValueMap[name]
+	}
+	if !ok || value == nil {
+		//传了空值或null时初始化指针，未传时保持nil
+		if pr := v.presenceMap[name]; fv.Kind() == reflect.Ptr && fv.IsNil() && (pr == PresenceEmpty || pr == PresenceNull) {
+			fv.Set(reflect.New(fv.Type().Elem()))
+			return true, nil
+		}
+		return false, nil
+	}
+	return true, setValue(indirect(fv), value, name, path)
+}
+
+//沿指针找到可以直接赋值为t类型的字段，nil指针会被初始化
+func assignTarget(fv reflect.Value, t reflect.Type) reflect.Value {
+	for {
+		if t.AssignableTo(fv.Type()) {
+			return fv
+		}
+		if fv.Kind() != reflect.Ptr || !t.AssignableTo(fv.Type().Elem()) && fv.Type().Elem().Kind() != reflect.Ptr {
+			return reflect.Value{}
+		}
+		fv = indirectOnce(fv)
+	}
+}
+
+//解引用所有指针，nil指针会被初始化
+func indirect(fv reflect.Value) reflect.Value {
+	for fv.Kind() == reflect.Ptr {
+		fv = indirectOnce(fv)
+	}
+	return fv
+}
+
+func indirectOnce(fv reflect.Value) reflect.Value {
+	if fv.IsNil() {
+		fv.Set(reflect.New(fv.Type().Elem()))
+	}
+	return fv.Elem()
+}
+
+func isTextUnmarshaler(t reflect.Type) bool {
+	return reflect.PtrTo(t).Implements(textUnmarshalerType)
+}
+
+func bindError(name, path string, fv reflect.Value, value interface{}) error {
+	return NewTextError(fmt.Sprintf("field %s of type %s can not bind param %s of type %T", path, fv.Type(), name, value))
+}
+
+//按字段类型赋值，支持所有整数、浮点、布尔、字符串类型及其命名类型、切片和encoding.TextUnmarshaler
+func setValue(fv reflect.Value, value interface{}, name, path string) error {
+	rv := reflect.ValueOf(value)
+	if !rv.IsValid() {
+		return nil
+	}
+	if rv.Type().AssignableTo(fv.Type()) {
+		fv.Set(rv)
+		return nil
+	}
+	if isTextUnmarshaler(fv.Type()) {
+		text := fmt.Sprint(value)
+		if err := fv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(text)); err != nil {
+			return NewTextError(fmt.Sprintf("field %s can not unmarshal param %s: %s", path, name, err.Error()))
+		}
+		return nil
+	}
+	switch fv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if !isIntKind(rv.Kind()) {
+			return bindError(name, path, fv, value)
+		}
+		n := rv.Int()
+		if fv.OverflowInt(n) {
+			return NewTextError(fmt.Sprintf("param %s value %d overflows field %s of type %s", name, n, path, fv.Type()))
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if !isIntKind(rv.Kind()) {
+			return bindError(name, path, fv, value)
+		}
+		n := rv.Int()
+		if n < 0 || fv.OverflowUint(uint64(n)) {
+			return NewTextError(fmt.Sprintf("param %s value %d overflows field %s of type %s", name, n, path, fv.Type()))
+		}
+		fv.SetUint(uint64(n))
+	case reflect.Float32, reflect.Float64:
+		switch {
+		case isIntKind(rv.Kind()):
+			fv.SetFloat(float64(rv.Int()))
+		case rv.Kind() == reflect.Float32 || rv.Kind() == reflect.Float64:
+			if fv.OverflowFloat(rv.Float()) {
+				return NewTextError(fmt.Sprintf("param %s value %v overflows field %s of type %s", name, value, path, fv.Type()))
+			}
+			fv.SetFloat(rv.Float())
+		default:
+			return bindError(name, path, fv, value)
+		}
+	case reflect.Bool:
+		if rv.Kind() != reflect.Bool {
+			return bindError(name, path, fv, value)
+		}
+		fv.SetBool(rv.Bool())
+	case reflect.String:
+		if rv.Kind() != reflect.String {
+			return bindError(name, path, fv, value)
+		}
+		fv.SetString(rv.String())
+	case reflect.Slice:
+		if rv.Kind() != reflect.Slice {
+			return bindError(name, path, fv, value)
+		}
+		sv := reflect.MakeSlice(fv.Type(), rv.Len(), rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			if err := setValue(indirect(sv.Index(i)), rv.Index(i).Interface(), name, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		fv.Set(sv)
+	default:
+		return bindError(name, path, fv, value)
+	}
+	return nil
+}
+
+func isIntKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
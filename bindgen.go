@@ -0,0 +1,242 @@
+package validator
+
+import (
+	"encoding"
+	"fmt"
+	"math"
+)
+
+//以下函数供validgen生成的绑定代码使用，语义与ValuesToStruct一致但不使用反射
+
+type Signed interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64
+}
+
+type Unsigned interface {
+	~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64
+}
+
+type Float interface {
+	~float32 | ~float64
+}
+
+//将单个值写入字段，name为参数名，path为字段路径，用于错误信息
+type SetFunc[T any] func(dst *T, value interface{}, name, path string) error
+
+//绑定参数到字段，依次使用格式解析值、校验后的值和默认值，返回字段是否被赋值
+func Bind[T any](v *Validator, name, path string, dst *T, set SetFunc[T]) (bool, error) {
+	if parsed, ok := v.parsedMap[name].(T); ok {
+		*dst = parsed
+		return true, nil
+	}
+	value, ok := v.bindValue(name)
+	if !ok || value == nil {
+		return false, nil
+	}
+	return true, set(dst, value, name, path)
+}
+
+//绑定参数到指针字段，参数传了空值或null时初始化指针，未传时保持nil
+func BindPtr[T any](v *Validator, name, path string, dst **T, set SetFunc[T]) (bool, error) {
+	if parsed, ok := v.parsedMap[name].(*T); ok {
+		*dst = parsed
+		return true, nil
+	}
+	if parsed, ok := v.parsedMap[name].(T); ok {
+		if *dst == nil {
+			*dst = new(T)
+		}
+		**dst = parsed
+		return true, nil
+	}
+	value, ok := v.bindValue(name)
+	if !ok || value == nil {
+		if pr := v.presenceMap[name]; *dst == nil && (pr == PresenceEmpty || pr == PresenceNull) {
+			*dst = new(T)
+			return true, nil
+		}
+		return false, nil
+	}
+	if *dst == nil {
+		*dst = new(T)
+	}
+	return true, set(*dst, value, name, path)
+}
+
+//绑定列表参数到切片字段
+func BindSlice[T any](v *Validator, name, path string, dst *[]T, set SetFunc[T]) (bool, error) {
+	return Bind(v, name, path, dst, setSlice(set))
+}
+
+//绑定列表参数到指针切片字段
+func BindPtrSlice[T any](v *Validator, name, path string, dst *[]*T, set SetFunc[T]) (bool, error) {
+	return Bind(v, name, path, dst, setSlice(setPtr(set)))
+}
+
+//绑定使用的值，未校验时为默认值
+func (v *Validator) bindValue(name string) (interface{}, bool) {
+	if value, ok := v.valueMap[name]; ok {
+		return value, true
+	}
+	value, ok := v.defaultValueMap[name]
+	return value, ok
+}
+
+//值类型相同或字段实现encoding.TextUnmarshaler时直接赋值
+func setDirect[T any](dst *T, value interface{}, name, path string) (bool, error) {
+	if x, ok := value.(T); ok {
+		*dst = x
+		return true, nil
+	}
+	if u, ok := any(dst).(encoding.TextUnmarshaler); ok {
+		if err := u.UnmarshalText([]byte(fmt.Sprint(value))); err != nil {
+			return true, NewTextError(fmt.Sprintf("field %s can not unmarshal param %s: %s", path, name, err.Error()))
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+func toInteger(value interface{}) (int64, bool) {
+	switch x := value.(type) {
+	case int:
+		return int64(x), true
+	case int8:
+		return int64(x), true
+	case int16:
+		return int64(x), true
+	case int32:
+		return int64(x), true
+	case int64:
+		return x, true
+	}
+	return 0, false
+}
+
+func typeBindError[T any](dst *T, value interface{}, name, path string) error {
+	return NewTextError(fmt.Sprintf("field %s of type %T can not bind param %s of type %T", path, *dst, name, value))
+}
+
+func overflowError[T any](dst *T, value interface{}, name, path string) error {
+	return NewTextError(fmt.Sprintf("param %s value %v overflows field %s of type %T", name, value, path, *dst))
+}
+
+func SetInt[T Signed](dst *T, value interface{}, name, path string) error {
+	if ok, err := setDirect(dst, value, name, path); ok {
+		return err
+	}
+	n, ok := toInteger(value)
+	if !ok {
+		return typeBindError(dst, value, name, path)
+	}
+	if int64(T(n)) != n {
+		return overflowError(dst, n, name, path)
+	}
+	*dst = T(n)
+	return nil
+}
+
+func SetUint[T Unsigned](dst *T, value interface{}, name, path string) error {
+	if ok, err := setDirect(dst, value, name, path); ok {
+		return err
+	}
+	n, ok := toInteger(value)
+	if !ok {
+		return typeBindError(dst, value, name, path)
+	}
+	if n < 0 || uint64(T(n)) != uint64(n) {
+		return overflowError(dst, n, name, path)
+	}
+	*dst = T(n)
+	return nil
+}
+
+func SetFloat[T Float](dst *T, value interface{}, name, path string) error {
+	if ok, err := setDirect(dst, value, name, path); ok {
+		return err
+	}
+	if n, ok := toInteger(value); ok {
+		*dst = T(n)
+		return nil
+	}
+	var f float64
+	switch x := value.(type) {
+	case float32:
+		f = float64(x)
+	case float64:
+		f = x
+	default:
+		return typeBindError(dst, value, name, path)
+	}
+	if math.IsInf(float64(T(f)), 0) && !math.IsInf(f, 0) {
+		return overflowError(dst, value, name, path)
+	}
+	*dst = T(f)
+	return nil
+}
+
+func SetBool[T ~bool](dst *T, value interface{}, name, path string) error {
+	if ok, err := setDirect(dst, value, name, path); ok {
+		return err
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return typeBindError(dst, value, name, path)
+	}
+	*dst = T(b)
+	return nil
+}
+
+func SetString[T ~string](dst *T, value interface{}, name, path string) error {
+	if ok, err := setDirect(dst, value, name, path); ok {
+		return err
+	}
+	s, ok := value.(string)
+	if !ok {
+		return typeBindError(dst, value, name, path)
+	}
+	*dst = T(s)
+	return nil
+}
+
+//其他类型只接受相同类型的值或encoding.TextUnmarshaler
+func SetAny[T any](dst *T, value interface{}, name, path string) error {
+	if ok, err := setDirect(dst, value, name, path); ok {
+		return err
+	}
+	return typeBindError(dst, value, name, path)
+}
+
+//切片字段，元素使用set赋值
+func setSlice[T any](set SetFunc[T]) SetFunc[[]T] {
+	return func(dst *[]T, value interface{}, name, path string) error {
+		if ok, err := setDirect(dst, value, name, path); ok {
+			return err
+		}
+		values, ok := value.([]interface{})
+		if !ok {
+			return typeBindError(dst, value, name, path)
+		}
+		s := make([]T, len(values))
+		for i, elem := range values {
+			if elem == nil {
+				continue
+			}
+			if err := set(&s[i], elem, name, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		*dst = s
+		return nil
+	}
+}
+
+//指针元素，nil指针会被初始化
+func setPtr[T any](set SetFunc[T]) SetFunc[*T] {
+	return func(dst **T, value interface{}, name, path string) error {
+		if *dst == nil {
+			*dst = new(T)
+		}
+		return set(*dst, value, name, path)
+	}
+}
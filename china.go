@@ -0,0 +1,162 @@
+package validator
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	mobileCNRegexp   = regexp.MustCompile(`^(?:\+?86)?1[3-9]\d{9}$`)
+	postcodeCNRegexp = regexp.MustCompile(`^[0-8]\d{5}$`)
+)
+
+var (
+	idCardWeights   = []int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+	idCardCheckCode = "10X98765432"
+
+	creditCodeChars   = "0123456789ABCDEFGHJKLMNPQRTUWXY"
+	creditCodeWeights = []int{1, 3, 9, 27, 19, 26, 16, 17, 20, 29, 25, 13, 8, 24, 10, 30, 28}
+)
+
+var (
+	mobileCNFormat = &format{
+		name:  "mobile_cn",
+		label: "手机号",
+		parse: func(s string) (interface{}, bool) { return s, mobileCNRegexp.MatchString(s) },
+		err:   (*ParamsError).ErrMustMobileCN,
+	}
+	idCardCNFormat = &format{
+		name:  "id_card_cn",
+		label: "身份证号",
+		parse: func(s string) (interface{}, bool) {
+			info, err := ParseIDCardCN(s)
+			return info, err == nil
+		},
+		err: (*ParamsError).ErrMustIDCardCN,
+	}
+	creditCodeCNFormat = &format{
+		name:  "credit_code_cn",
+		label: "统一社会信用代码",
+		parse: func(s string) (interface{}, bool) { return s, isCreditCodeCN(s) },
+		err:   (*ParamsError).ErrMustCreditCodeCN,
+	}
+	postcodeCNFormat = &format{
+		name:  "postcode_cn",
+		label: "邮政编码",
+		parse: func(s string) (interface{}, bool) { return s, postcodeCNRegexp.MatchString(s) },
+		err:   (*ParamsError).ErrMustPostcodeCN,
+	}
+	bankCardFormat = &format{
+		name:  "bank_card",
+		label: "银行卡号",
+		parse: func(s string) (interface{}, bool) { return s, len(s) >= 12 && len(s) <= 19 && luhn(s) },
+		err:   (*ParamsError).ErrMustBankCard,
+	}
+)
+
+const (
+	GenderFemale = "female"
+	GenderMale   = "male"
+)
+
+//身份证号中可提取的信息
+type IDCardInfo struct {
+	Region   string
+	Birthday time.Time
+	Gender   string
+}
+
+//解析18位身份证号，校验出生日期及校验码
+func ParseIDCardCN(s string) (*IDCardInfo, error) {
+	if len(s) != 18 {
+		return nil, errors.New("身份证号必须为18位")
+	}
+	s = strings.ToUpper(s)
+	sum := 0
+	for i := 0; i < 17; i++ {
+		c := s[i]
+		if c < '0' || c > '9' {
+			return nil, errors.New("身份证号前17位必须为数字")
+		}
+		sum += int(c-'0') * idCardWeights[i]
+	}
+	if s[17] != idCardCheckCode[sum%11] {
+		return nil, errors.New("身份证号校验码错误")
+	}
+	birthday, err := time.ParseInLocation("20060102", s[6:14], time.Local)
+	if err != nil || birthday.Year() < 1900 || birthday.After(time.Now()) {
+		return nil, errors.New("身份证号出生日期错误")
+	}
+	info := &IDCardInfo{
+		Region:   s[:6],
+		Birthday: birthday,
+		Gender:   GenderFemale,
+	}
+	if (s[16]-'0')%2 == 1 {
+		info.Gender = GenderMale
+	}
+	return info, nil
+}
+
+func isCreditCodeCN(s string) bool {
+	if len(s) != 18 {
+		return false
+	}
+	s = strings.ToUpper(s)
+	sum := 0
+	for i := 0; i < 17; i++ {
+		n := strings.IndexByte(creditCodeChars, s[i])
+		if n < 0 {
+			return false
+		}
+		sum += n * creditCodeWeights[i]
+	}
+	check := (31 - sum%31) % 31
+	return s[17] == creditCodeChars[check]
+}
+
+//Luhn校验
+func luhn(s string) bool {
+	sum := 0
+	double := false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		n := int(c - '0')
+		if double {
+			n *= 2
+			if n > 9 {
+				n -= 9
+			}
+		}
+		sum += n
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+//中国大陆手机号，允许+86前缀
+func (r *ruleSet) MustMobileCN() RuleSet {
+	return r.mustFormat("MustMobileCN", mobileCNFormat)
+}
+
+//18位居民身份证号，可使用ParseIDCardCN提取出生日期和性别
+func (r *ruleSet) MustIDCardCN() RuleSet {
+	return r.mustFormat("MustIDCardCN", idCardCNFormat)
+}
+
+func (r *ruleSet) MustCreditCodeCN() RuleSet {
+	return r.mustFormat("MustCreditCodeCN", creditCodeCNFormat)
+}
+
+func (r *ruleSet) MustPostcodeCN() RuleSet {
+	return r.mustFormat("MustPostcodeCN", postcodeCNFormat)
+}
+
+func (r *ruleSet) MustBankCard() RuleSet {
+	return r.mustFormat("MustBankCard", bankCardFormat)
+}
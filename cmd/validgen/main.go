@@ -0,0 +1,74 @@
+//validgen根据结构体的valid和validate标签生成不使用反射的BindFrom方法和Validator构造函数
+//
+//用法：
+//
+//	//go:generate validgen -type=ListRequest
+//
+//默认读取当前目录下的所有go文件，生成<类型名小写>_valid.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-wyvern/validator/internal/gen"
+)
+
+var (
+	typeNames = flag.String("type", "", "comma-separated list of struct type names; must be set")
+	output    = flag.String("output", "", "output file name; default <type>_valid.go")
+	qualifier = flag.String("qualifier", "validator.", "prefix used to refer to the validator package; empty inside the package itself")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage of validgen:\n")
+	fmt.Fprintf(os.Stderr, "\tvalidgen -type T [-output file] [files...]\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	types := strings.Split(*typeNames, ",")
+	out := *output
+	if out == "" {
+		out = strings.ToLower(types[0]) + "_valid.go"
+	}
+
+	files := flag.Args()
+	if len(files) == 0 {
+		matches, err := filepath.Glob("*.go")
+		if err != nil {
+			fatal(err)
+		}
+		for _, name := range matches {
+			if name != out && !strings.HasSuffix(name, "_test.go") {
+				files = append(files, name)
+			}
+		}
+	}
+
+	src, err := gen.GenerateFiles(files, gen.Config{
+		Types:     types,
+		Qualifier: *qualifier,
+		Command:   "validgen",
+	})
+	if err != nil {
+		fatal(err)
+	}
+	if err := os.WriteFile(out, src, 0644); err != nil {
+		fatal(err)
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintf(os.Stderr, "validgen: %v\n", err)
+	os.Exit(1)
+}
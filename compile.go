@@ -0,0 +1,190 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+//只能用于字符串参数的规则
+var stringRules = map[string]bool{
+	"MustLength":           true,
+	"MustLengthRange":      true,
+	"MustValuesIgnoreCase": true,
+	"MustTimeLayout":       true,
+	"MustMatch":            true,
+	"MustNotMatch":         true,
+	"MustPrefix":           true,
+	"MustSuffix":           true,
+	"MustContains":         true,
+	"MustASCII":            true,
+	"MustAlphanumeric":     true,
+	"MustDigits":           true,
+	"MustHan":              true,
+	"MustNoEmoji":          true,
+	"MustNoControl":        true,
+	"MustPasswordPolicy":   true,
+	"MustNotContainWords":  true,
+}
+
+var (
+	lowerBoundRules = map[string]bool{"MustMin": true, "MustMinValue": true, "MustGreaterThanValue": true}
+	upperBoundRules = map[string]bool{"MustMax": true, "MustMaxValue": true, "MustLessThanValue": true}
+)
+
+//记录声明时发现的错误，之后该ruleSet上的声明会被忽略，由Compile返回
+func (r *ruleSet) fail(err error) RuleSet {
+	r.setError = fmt.Errorf("param %s: %s", r.paramName, err.Error())
+	r.valid.declErrors = append(r.valid.declErrors, r.setError)
+	return r
+}
+
+//layout至少包含一个时间元素，且按layout格式化后能解析回来
+func checkTimeLayout(layout string) error {
+	ref := time.Date(2017, 11, 23, 21, 34, 56, 0, time.UTC)
+	formatted := ref.Format(layout)
+	if formatted == layout {
+		return fmt.Errorf("time layout %q contains no layout element", layout)
+	}
+	if _, err := time.Parse(layout, formatted); err != nil {
+		return fmt.Errorf("invalid time layout %q: %s", layout, err.Error())
+	}
+	return nil
+}
+
+//参数值的类型，列表参数为元素类型
+func (v *Validator) valueKind(name string) reflect.Kind {
+	kind, ok := v.typeMap[name]
+	if !ok {
+		return reflect.String
+	}
+	if kind == reflect.Slice {
+		return v.elemTypeMap[name]
+	}
+	return kind
+}
+
+//检查声明中的错误，返回所有问题，通过后才能注册Api
+func (v *Validator) Compile() error {
+	errs := append([]error(nil), v.declErrors...)
+	var names []string
+	for name := range v.ApiParams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, err := range v.compileParam(name) {
+			errs = append(errs, fmt.Errorf("param %s: %s", name, err.Error()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (v *Validator) compileParam(name string) []error {
+	var errs []error
+	kind := v.valueKind(name)
+	var lower, upper *rule
+	var formats []string
+	for i := range v.ruleMap[name] {
+		rl := &v.ruleMap[name][i]
+		if rl.name == "" {
+			continue
+		}
+		if len(rl.args) > 0 {
+			if f, ok := rl.args[0].(*format); ok && !containsString(formats, f.name) {
+				formats = append(formats, f.name)
+			}
+		}
+		if stringRules[rl.name] && kind != reflect.String {
+			errs = append(errs, fmt.Errorf("%s requires a string param, got %s", rl.name, v.kindName(name, kind)))
+		}
+		if rl.fieldArg {
+			if _, ok := v.ApiParams[rl.args[0].(string)]; !ok {
+				errs = append(errs, fmt.Errorf("%s refers to undeclared param %s", rl.name, rl.args[0]))
+			}
+		}
+		if lowerBoundRules[rl.name] || upperBoundRules[rl.name] {
+			if err := v.checkBound(name, kind, rl); err != nil {
+				errs = append(errs, err)
+			}
+			if lowerBoundRules[rl.name] {
+				lower = rl
+			} else {
+				upper = rl
+			}
+		}
+		if _, ok := valuesDocs[rl.name]; ok && kind != reflect.Interface {
+			for _, value := range rl.args[0].([]interface{}) {
+				if err := checkValueKind(value, kind); err != nil {
+					errs = append(errs, fmt.Errorf("%s value %s", rl.name, err.Error()))
+				}
+			}
+		}
+		if rl.name == "MustLengthRange" && rl.args[0].(int) > rl.args[1].(int) {
+			errs = append(errs, fmt.Errorf("MustLengthRange min %v is greater than max %v", rl.args[0], rl.args[1]))
+		}
+	}
+	if lower != nil && upper != nil {
+		c, ok := compareBound(lower.args[0], upper.args...)
+		exclusive := lower.name == "MustGreaterThanValue" || upper.name == "MustLessThanValue"
+		if ok && (c > 0 || c == 0 && exclusive) {
+			errs = append(errs, fmt.Errorf("%s(%v) and %s(%v) can never both be satisfied", lower.name, lower.args[0], upper.name, upper.args[0]))
+		}
+	}
+	if len(formats) > 1 {
+		errs = append(errs, fmt.Errorf("only one format is allowed, got %s", strings.Join(formats, ", ")))
+	}
+	if f, ok := v.formatMap[name]; ok && kind != reflect.String {
+		errs = append(errs, fmt.Errorf("format %s requires a string param, got %s", f.name, v.kindName(name, kind)))
+	}
+	return append(errs, v.checkDefault(name)...)
+}
+
+//边界需能与参数值比较：数值边界用于数值参数，时间边界用于声明了MustTimeLayout的字符串参数
+func (v *Validator) checkBound(name string, kind reflect.Kind, rl *rule) error {
+	bound := rl.args[0]
+	if _, isTime := bound.(time.Time); isTime {
+		if kind != reflect.String {
+			return fmt.Errorf("%s with a time bound requires a string param, got %s", rl.name, v.kindName(name, kind))
+		}
+		if _, ok := v.timeLayoutMap[name]; !ok {
+			return fmt.Errorf("%s with a time bound requires MustTimeLayout", rl.name)
+		}
+		return nil
+	}
+	if _, ok := toFloat64(bound); !ok {
+		return fmt.Errorf("%s bound %v(%T) is not comparable", rl.name, bound, bound)
+	}
+	if kind != reflect.Int && kind != reflect.Int64 && kind != reflect.Float64 {
+		return fmt.Errorf("%s requires a numeric param, got %s", rl.name, v.kindName(name, kind))
+	}
+	return nil
+}
+
+//值的Go类型需与ValuesToStruct中使用的类型一致
+func checkValueKind(value interface{}, kind reflect.Kind) error {
+	var ok bool
+	switch kind {
+	case reflect.Int:
+		_, ok = value.(int)
+	case reflect.Int64:
+		_, ok = value.(int64)
+	case reflect.Float64:
+		_, ok = value.(float64)
+	case reflect.Bool:
+		_, ok = value.(bool)
+	case reflect.String:
+		_, ok = value.(string)
+	case reflect.Slice:
+		_, ok = value.([]interface{})
+	default:
+		ok = true
+	}
+	if !ok {
+		return fmt.Errorf("%v(%T) is not %s", value, value, kind)
+	}
+	return nil
+}
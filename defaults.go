@@ -0,0 +1,189 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+//按参数声明的类型转换默认值，类型声明变化时重新转换，无法转换时保留原值由Compile报告
+func (r *ruleSet) coerceDefault() RuleSet {
+	value, ok := r.valid.defaultValueMap[r.paramName]
+	if !ok {
+		return r
+	}
+	p := r.valid.ApiParams[r.paramName]
+	p.Default = value
+	p.HasDefault = true
+	if coerced, err := r.valid.coerceValue(r.paramName, value); err == nil {
+		r.valid.defaultValueMap[r.paramName] = coerced
+		p.Default = coerced
+	}
+	return r
+}
+
+//转换为valueCheck解析后的类型，列表参数可以是分隔后的字符串或任意切片
+func (v *Validator) coerceValue(name string, value interface{}) (interface{}, error) {
+	kind, ok := v.typeMap[name]
+	if !ok {
+		kind = reflect.String
+	}
+	if kind != reflect.Slice {
+		return v.convertParamValue(name, value, kind)
+	}
+	var elems []interface{}
+	switch vt := value.(type) {
+	case string:
+		for _, s := range strings.Split(vt, v.separator(name)) {
+			elems = append(elems, s)
+		}
+	case []interface{}:
+		elems = vt
+	default:
+		rv := reflect.ValueOf(value)
+		if rv.Kind() != reflect.Slice {
+			return nil, fmt.Errorf("value %v(%T) is not a slice", value, value)
+		}
+		for i := 0; i < rv.Len(); i++ {
+			elems = append(elems, rv.Index(i).Interface())
+		}
+	}
+	converted := make([]interface{}, 0, len(elems))
+	for _, elem := range elems {
+		cv, err := v.convertParamValue(name, elem, v.elemTypeMap[name])
+		if err != nil {
+			return nil, err
+		}
+		converted = append(converted, cv)
+	}
+	return converted, nil
+}
+
+//默认值需满足参数自身的规则
+func (v *Validator) checkDefault(name string) []error {
+	value, ok := v.defaultValueMap[name]
+	if !ok {
+		return nil
+	}
+	if err := checkValueKind(value, v.typeMap[name]); err != nil {
+		return []error{fmt.Errorf("default value: %s", err.Error())}
+	}
+	var errs []error
+	if f, ok := v.formatMap[name]; ok {
+		if s, isString := value.(string); isString {
+			if _, ok := f.parse(s); !ok {
+				errs = append(errs, fmt.Errorf("default value %q is not a valid %s", s, f.name))
+			}
+		}
+	}
+	for _, rl := range v.ruleMap[name] {
+		if rl.f == nil || rl.fieldArg {
+			continue
+		}
+		if err := rl.f(name, value, nil, false, v.ruleArgs(name, rl)...); err != nil {
+			errs = append(errs, fmt.Errorf("default value: %s", err.Error()))
+		}
+	}
+	return errs
+}
+
+//校验通过后，未传值的参数使用默认值，之后按声明顺序计算动态默认值和计算参数
+func (v *Validator) applyDefaults() error {
+	for name, value := range v.defaultValueMap {
+		if v.presenceMap[name] != PresenceAbsent {
+			continue
+		}
+		v.valueMap[name] = value
+	}
+	for _, d := range v.defaultFuncs {
+		if v.presenceMap[d.name] != PresenceAbsent {
+			continue
+		}
+		value, err := v.coerceValue(d.name, d.f(v))
+		if err != nil {
+			return NewTextError(fmt.Sprintf("default value of param %s: %s", d.name, err.Error()))
+		}
+		v.valueMap[d.name] = value
+	}
+	for _, c := range v.computed {
+		value, err := c.f(v)
+		if err != nil {
+			return err
+		}
+		if value, err = convertValue(value, c.kind); err != nil {
+			return NewTextError(fmt.Sprintf("computed param %s: %s", c.name, err.Error()))
+		}
+		v.valueMap[c.name] = value
+	}
+	return nil
+}
+
+//文档中展示的默认值
+func (p *Params) DefaultDoc() string {
+	if p.DynamicDefault {
+		return "动态"
+	}
+	if !p.HasDefault {
+		return ""
+	}
+	return fmt.Sprint(p.Default)
+}
+
+//读取其他参数校验后的值
+type ValueGetter interface {
+	Value(string) (interface{}, bool)
+	Int(string) int
+	Int64(string) int64
+	Float64(string) float64
+	Bool(string) bool
+	String(string) string
+	Slice(string) []interface{}
+	Presence(string) Presence
+}
+
+var _ ValueGetter = new(Validator)
+
+//动态默认值，后声明的可以读取先声明的结果
+type defaultFunc struct {
+	name string
+	f    func(ValueGetter) interface{}
+}
+
+//计算参数，由其他参数的值计算得到，不接受请求传入
+type computedParam struct {
+	name string
+	kind reflect.Kind
+	f    func(ValueGetter) (interface{}, error)
+}
+
+//动态默认值，校验通过且参数未传值时按声明顺序计算，优先于静态默认值，重复声明时替换之前的函数
+func (r *ruleSet) DefaultFunc(f func(ValueGetter) interface{}) RuleSet {
+	r.valid.own()
+	if r.setError != nil {
+		return r
+	}
+	if r.paramName == "" {
+		panic("unknown param name when set DefaultFunc")
+	}
+	r.valid.setDefaultFunc(r.paramName, f)
+	r.valid.ApiParams[r.paramName].DynamicDefault = true
+	return r
+}
+
+func (v *Validator) setDefaultFunc(name string, f func(ValueGetter) interface{}) {
+	for i := range v.defaultFuncs {
+		if v.defaultFuncs[i].name == name {
+			v.defaultFuncs[i].f = f
+			return
+		}
+	}
+	v.defaultFuncs = append(v.defaultFuncs, defaultFunc{name: name, f: f})
+}
+
+//声明计算参数，按声明顺序在校验通过后计算，可使用之前声明的计算参数，结果可通过ValuesToStruct绑定
+func (v *Validator) Computed(name string, kind reflect.Kind, f func(ValueGetter) (interface{}, error)) *Validator {
+	v.own()
+	v.computed = append(v.computed, computedParam{name: name, kind: kind, f: f})
+	v.typeMap[name] = kind
+	return v
+}
@@ -0,0 +1,210 @@
+package validator
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	timeType          = reflect.TypeOf(time.Time{})
+)
+
+//按照valid标签和参数声明将结构体编码为url.Values，编码结果会先经过校验，与ValuesToStruct互为逆过程
+//nil指针字段不编码，零值的非指针字段在参数没有默认值时不编码，有默认值时照常编码，避免被默认值替换
+func (v *Validator) Encode(src interface{}) (url.Values, error) {
+	sv := reflect.ValueOf(src)
+	for sv.Kind() == reflect.Ptr {
+		if sv.IsNil() {
+			return nil, NewTextError("encode source must not be nil")
+		}
+		sv = sv.Elem()
+	}
+	if sv.Kind() != reflect.Struct {
+		return nil, NewTextError("encode source must be a struct or a pointer to struct")
+	}
+	values := url.Values{}
+	if err := v.encodeStruct(values, sv, ""); err != nil {
+		return nil, err
+	}
+	if err := Validate(values, v.derived()); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func (v *Validator) encodeStruct(values url.Values, sv reflect.Value, path string) error {
+	st := sv.Type()
+	for i := 0; i < st.NumField(); i++ {
+		sf := st.Field(i)
+		if sf.PkgPath != "" && !sf.Anonymous {
+			continue
+		}
+		fv := sv.Field(i)
+		fieldPath := sf.Name
+		if path != "" {
+			fieldPath = path + "." + sf.Name
+		}
+		name, tagged := sf.Tag.Lookup(ValidTag)
+		if name == "-" {
+			continue
+		}
+		if !tagged || name == "" {
+			if err := v.encodeNested(values, fv, fieldPath); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, ok := v.ApiParams[name]; !ok {
+			if v.isComputed(name) {
+				continue
+			}
+			return NewTextError(fmt.Sprintf("field %s refers to undeclared param %s", fieldPath, name))
+		}
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				continue
+			}
+			fv = reflect.Indirect(fv)
+		} else if fv.IsZero() && !v.ApiParams[name].HasDefault && !v.ApiParams[name].DynamicDefault {
+			continue
+		}
+		text, err := v.encodeField(name, fv, fieldPath)
+		if err != nil {
+			return err
+		}
+		values.Set(name, text)
+	}
+	return nil
+}
+
+//未打标签的结构体或结构体指针递归编码
+func (v *Validator) encodeNested(values url.Values, fv reflect.Value, path string) error {
+	if fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			return nil
+		}
+		fv = fv.Elem()
+	}
+	if fv.Kind() != reflect.Struct || fv.Type() == timeType || reflect.PtrTo(fv.Type()).Implements(textMarshalerType) {
+		return nil
+	}
+	return v.encodeStruct(values, fv, path)
+}
+
+func (v *Validator) isComputed(name string) bool {
+	for _, c := range v.computed {
+		if c.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+//列表参数的元素用分隔符连接，元素中不能包含分隔符
+func (v *Validator) encodeField(name string, fv reflect.Value, path string) (string, error) {
+	if v.typeMap[name] != reflect.Slice {
+		return v.encodeValue(name, v.typeMap[name], fv, path)
+	}
+	if fv.Kind() != reflect.Slice && fv.Kind() != reflect.Array {
+		return "", NewTextError(fmt.Sprintf("field %s of type %s can not encode list param %s", path, fv.Type(), name))
+	}
+	sep := v.separator(name)
+	elems := make([]string, 0, fv.Len())
+	for i := 0; i < fv.Len(); i++ {
+		text, err := v.encodeValue(name, v.elemTypeMap[name], reflect.Indirect(fv.Index(i)), fmt.Sprintf("%s[%d]", path, i))
+		if err != nil {
+			return "", err
+		}
+		if sep != "" && strings.Contains(text, sep) {
+			return "", NewTextError(fmt.Sprintf("field %s value %q contains separator %q of param %s", path, text, sep, name))
+		}
+		elems = append(elems, text)
+	}
+	return strings.Join(elems, sep), nil
+}
+
+//按参数声明的类型格式化：数值和布尔使用strconv，字符串参数依次使用时间格式、TextMarshaler和Stringer
+func (v *Validator) encodeValue(name string, kind reflect.Kind, fv reflect.Value, path string) (string, error) {
+	if !fv.IsValid() {
+		return "", nil
+	}
+	switch kind {
+	case reflect.Int, reflect.Int64:
+		switch fv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return strconv.FormatInt(fv.Int(), 10), nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return strconv.FormatUint(fv.Uint(), 10), nil
+		}
+	case reflect.Float64:
+		switch fv.Kind() {
+		case reflect.Float32, reflect.Float64:
+			return strconv.FormatFloat(fv.Float(), 'f', -1, fv.Type().Bits()), nil
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return strconv.FormatInt(fv.Int(), 10), nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return strconv.FormatUint(fv.Uint(), 10), nil
+		}
+	case reflect.Bool:
+		if fv.Kind() == reflect.Bool {
+			return strconv.FormatBool(fv.Bool()), nil
+		}
+	case reflect.Interface:
+		if t, ok := v.customTypeMap[name]; ok {
+			text, err := t.format(fv.Interface())
+			if err != nil {
+				return "", NewTextError(fmt.Sprintf("field %s can not format param %s as %s: %s", path, name, t.name, err.Error()))
+			}
+			return text, nil
+		}
+	default:
+		if layout, ok := v.timeLayoutMap[name]; ok && fv.Type() == timeType {
+			return fv.Interface().(time.Time).Format(layout), nil
+		}
+		if m, ok := asTextMarshaler(fv); ok {
+			text, err := m.MarshalText()
+			if err != nil {
+				return "", NewTextError(fmt.Sprintf("field %s can not marshal param %s: %s", path, name, err.Error()))
+			}
+			return string(text), nil
+		}
+		if s, ok := fv.Interface().(fmt.Stringer); ok {
+			return s.String(), nil
+		}
+		if fv.CanAddr() {
+			if s, ok := fv.Addr().Interface().(fmt.Stringer); ok {
+				return s.String(), nil
+			}
+		}
+		switch fv.Kind() {
+		case reflect.String:
+			return fv.String(), nil
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return strconv.FormatInt(fv.Int(), 10), nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return strconv.FormatUint(fv.Uint(), 10), nil
+		case reflect.Float32, reflect.Float64:
+			return strconv.FormatFloat(fv.Float(), 'f', -1, fv.Type().Bits()), nil
+		case reflect.Bool:
+			return strconv.FormatBool(fv.Bool()), nil
+		}
+	}
+	return "", NewTextError(fmt.Sprintf("field %s of type %s can not encode param %s of type %s", path, fv.Type(), name, kind))
+}
+
+func asTextMarshaler(fv reflect.Value) (encoding.TextMarshaler, bool) {
+	if m, ok := fv.Interface().(encoding.TextMarshaler); ok {
+		return m, true
+	}
+	if fv.CanAddr() {
+		m, ok := fv.Addr().Interface().(encoding.TextMarshaler)
+		return m, ok
+	}
+	return nil, false
+}
@@ -1,40 +1,198 @@
 package validator
 
-import "bytes"
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	texttemplate "text/template"
+)
+
+//错误码，同时作为errors.Is使用的哨兵错误
+type ErrorCode string
+
+func (c ErrorCode) Error() string {
+	return string(c)
+}
+
+const (
+	CodeUnknownParam         ErrorCode = "unknown_param"
+	CodeRequireParam         ErrorCode = "require_param"
+	CodeRequireNotNull       ErrorCode = "require_not_null"
+	CodeForbiddenParam       ErrorCode = "forbidden_param"
+	CodeMustNotEmpty         ErrorCode = "must_not_empty"
+	CodeMustInt              ErrorCode = "must_int"
+	CodeMustInt64            ErrorCode = "must_int64"
+	CodeMustFloat64          ErrorCode = "must_float64"
+	CodeMustBool             ErrorCode = "must_bool"
+	CodeMustType             ErrorCode = "must_type"
+	CodeMustLength           ErrorCode = "must_length"
+	CodeMustMin              ErrorCode = "must_min"
+	CodeMustMax              ErrorCode = "must_max"
+	CodeMustGreaterThanValue ErrorCode = "must_greater_than_value"
+	CodeMustLessThanValue    ErrorCode = "must_less_than_value"
+	CodeMustLengthRange      ErrorCode = "must_length_range"
+	CodeMustValues           ErrorCode = "must_values"
+	CodeMustNotValues        ErrorCode = "must_not_values"
+	CodeMustTimeLayout       ErrorCode = "must_time_layout"
+	CodeMustMatch            ErrorCode = "must_match"
+	CodeMustNotMatch         ErrorCode = "must_not_match"
+	CodeMustPrefix           ErrorCode = "must_prefix"
+	CodeMustSuffix           ErrorCode = "must_suffix"
+	CodeMustContains         ErrorCode = "must_contains"
+	CodeMustASCII            ErrorCode = "must_ascii"
+	CodeMustAlphanumeric     ErrorCode = "must_alphanumeric"
+	CodeMustDigits           ErrorCode = "must_digits"
+	CodeMustHan              ErrorCode = "must_han"
+	CodeMustNoEmoji          ErrorCode = "must_no_emoji"
+	CodeMustNoControl        ErrorCode = "must_no_control"
+	CodeMustEmail            ErrorCode = "must_email"
+	CodeMustURL              ErrorCode = "must_url"
+	CodeMustIP               ErrorCode = "must_ip"
+	CodeMustIPv4             ErrorCode = "must_ipv4"
+	CodeMustIPv6             ErrorCode = "must_ipv6"
+	CodeMustCIDR             ErrorCode = "must_cidr"
+	CodeMustHostname         ErrorCode = "must_hostname"
+	CodeMustPort             ErrorCode = "must_port"
+	CodeMustMAC              ErrorCode = "must_mac"
+	CodeMustUUID             ErrorCode = "must_uuid"
+	CodeMustHex              ErrorCode = "must_hex"
+	CodeMustBase64           ErrorCode = "must_base64"
+	CodeMustSemver           ErrorCode = "must_semver"
+	CodeMustMobileCN         ErrorCode = "must_mobile_cn"
+	CodeMustIDCardCN         ErrorCode = "must_id_card_cn"
+	CodeMustCreditCodeCN     ErrorCode = "must_credit_code_cn"
+	CodeMustPostcodeCN       ErrorCode = "must_postcode_cn"
+	CodeMustBankCard         ErrorCode = "must_bank_card"
+	CodeMustPasswordPolicy   ErrorCode = "must_password_policy"
+	CodeMustNotContainWords  ErrorCode = "must_not_contain_words"
+	CodeMustLessThan         ErrorCode = "must_less_than"
+	CodeMustLargeThan        ErrorCode = "must_large_than"
+	CodeMustFunc             ErrorCode = "must_func"
+)
 
 type ParamsError struct {
-	Key   string
-	Value interface{}
-	Args  []interface{}
-	Text  string
+	Code  ErrorCode     `json:"code"`
+	Key   string        `json:"param"`
+	Label string        `json:"label,omitempty"`
+	Value interface{}   `json:"-"`
+	Args  []interface{} `json:"args,omitempty"`
+	Text  string        `json:"msg"`
+	tpl   string
 }
 
+//多个参数错误
+type ParamsErrors []*ParamsError
+
 var (
-	defaultUnknownParamTpl    = "未知的参数:{{.Key}}"
-	defaultRequireParamTpl    = "{{.Key}}是必须的参数"
-	defaultRequireNotNullTpl  = "{{.Key}}是必须的参数，不能为空"
-	defaultMustLengthTpl      = "参数[{{.Key}}]的长度必须为{{index .Args 0}}"
-	defaultMustMinTpl         = "参数[{{.Key}}]的最小值必须大于{{index .Args 0}}"
-	defaultMustMaxTpl         = "参数[{{.Key}}]的最大值必须小于{{index .Args 0}}"
-	defaultMustLengthRangeTpl = "参数[{{.Key}}]的长度必须为大于{{index .Args 0}}小于{{index .Args 1}}"
-	defaultMustValuesTpl      = `参数[{{.Key}}]的值必须在{{index .Args 0}}的范围中`
-	defaultMustTimeLayoutTpl  = "参数[{{.Key}}]的格式必须是{{index .Args 0}}"
-	defaultMustLessThanTpl    = "参数[{{.Key}}]的值必须小于参数[{{index .Args 0}}]"
-	defaultMustLargeThanTpl   = "参数[{{.Key}}]的值必须大于参数[{{index .Args 0}}]"
+	defaultUnknownParamTpl         = "未知的参数:{{.Label}}"
+	defaultRequireParamTpl         = "{{.Label}}是必须的参数"
+	defaultRequireNotNullTpl       = "{{.Label}}是必须的参数，不能为空"
+	defaultForbiddenParamTpl       = "不允许传入参数[{{.Label}}]"
+	defaultMustNotEmptyTpl         = "参数[{{.Label}}]不能为空"
+	defaultMustIntTpl              = "参数[{{.Label}}]格式错误,参数值必须是int类型"
+	defaultMustInt64Tpl            = "参数[{{.Label}}]格式错误,参数值必须是int64类型"
+	defaultMustFloat64Tpl          = "参数[{{.Label}}]格式错误,参数值必须是float64类型"
+	defaultMustBoolTpl             = "参数[{{.Label}}]格式错误,参数值必须是bool类型"
+	defaultMustFuncTpl             = "参数[{{.Label}}]校验失败"
+	defaultMustTypeTpl             = "参数[{{.Label}}]格式错误,参数值必须是{{index .Args 0}}类型"
+	defaultMustLengthTpl           = "参数[{{.Label}}]的长度必须为{{index .Args 0}}"
+	defaultMustMinTpl              = "参数[{{.Label}}]的值必须大于等于{{index .Args 0}}"
+	defaultMustMaxTpl              = "参数[{{.Label}}]的值必须小于等于{{index .Args 0}}"
+	defaultMustGreaterThanValueTpl = "参数[{{.Label}}]的值必须大于{{index .Args 0}}"
+	defaultMustLessThanValueTpl    = "参数[{{.Label}}]的值必须小于{{index .Args 0}}"
+	defaultMustLengthRangeTpl      = "参数[{{.Label}}]的长度必须为大于等于{{index .Args 0}}小于等于{{index .Args 1}}"
+	defaultMustValuesTpl           = `参数[{{.Label}}]的值必须在{{index .Args 0}}的范围中`
+	defaultMustNotValuesTpl        = `参数[{{.Label}}]的值不能是{{index .Args 0}}中的值`
+	defaultMustTimeLayoutTpl       = "参数[{{.Label}}]的格式必须是{{index .Args 0}}"
+	defaultMustMatchTpl            = "参数[{{.Label}}]的格式不正确,必须匹配{{index .Args 0}}"
+	defaultMustNotMatchTpl         = "参数[{{.Label}}]的格式不正确,不能匹配{{index .Args 0}}"
+	defaultMustPrefixTpl           = "参数[{{.Label}}]必须以{{index .Args 0}}开头"
+	defaultMustSuffixTpl           = "参数[{{.Label}}]必须以{{index .Args 0}}结尾"
+	defaultMustContainsTpl         = "参数[{{.Label}}]必须包含{{index .Args 0}}"
+	defaultMustASCIITpl            = "参数[{{.Label}}]只能包含ASCII字符"
+	defaultMustAlphanumericTpl     = "参数[{{.Label}}]只能包含字母和数字"
+	defaultMustDigitsTpl           = "参数[{{.Label}}]只能包含数字"
+	defaultMustHanTpl              = "参数[{{.Label}}]只能包含汉字"
+	defaultMustNoEmojiTpl          = "参数[{{.Label}}]不能包含表情符号"
+	defaultMustNoControlTpl        = "参数[{{.Label}}]不能包含控制字符"
+	defaultMustEmailTpl            = "参数[{{.Label}}]必须是有效的邮箱地址"
+	defaultMustURLTpl              = "参数[{{.Label}}]必须是有效的URL"
+	defaultMustIPTpl               = "参数[{{.Label}}]必须是有效的IP地址"
+	defaultMustIPv4Tpl             = "参数[{{.Label}}]必须是有效的IPv4地址"
+	defaultMustIPv6Tpl             = "参数[{{.Label}}]必须是有效的IPv6地址"
+	defaultMustCIDRTpl             = "参数[{{.Label}}]必须是有效的CIDR"
+	defaultMustHostnameTpl         = "参数[{{.Label}}]必须是有效的主机名"
+	defaultMustPortTpl             = "参数[{{.Label}}]必须是有效的端口号"
+	defaultMustMACTpl              = "参数[{{.Label}}]必须是有效的MAC地址"
+	defaultMustUUIDTpl             = "参数[{{.Label}}]必须是有效的UUID"
+	defaultMustHexTpl              = "参数[{{.Label}}]必须是十六进制字符串"
+	defaultMustBase64Tpl           = "参数[{{.Label}}]必须是base64编码"
+	defaultMustSemverTpl           = "参数[{{.Label}}]必须是有效的语义化版本号"
+	defaultMustMobileCNTpl         = "参数[{{.Label}}]必须是有效的手机号"
+	defaultMustIDCardCNTpl         = "参数[{{.Label}}]必须是有效的身份证号"
+	defaultMustCreditCodeCNTpl     = "参数[{{.Label}}]必须是有效的统一社会信用代码"
+	defaultMustPostcodeCNTpl       = "参数[{{.Label}}]必须是有效的邮政编码"
+	defaultMustBankCardTpl         = "参数[{{.Label}}]必须是有效的银行卡号"
+	defaultMustPasswordPolicyTpl   = "参数[{{.Label}}]强度不足,未满足:{{range $i, $item := .Args}}{{if $i}},{{end}}{{$item}}{{end}}"
+	defaultMustNotContainWordsTpl  = "参数[{{.Label}}]包含敏感词"
+	defaultMustLessThanTpl         = "参数[{{.Label}}]的值必须小于参数[{{index .Args 0}}]"
+	defaultMustLargeThanTpl        = "参数[{{.Label}}]的值必须大于参数[{{index .Args 0}}]"
 )
 
 var (
-	CustomUnknownParamTpl    = "{{.unknow_param}}"
-	CustomRequireParamTpl    = "{{.require_param}}"
-	CustomRequireNotNullTpl  = "{{.require_not_null}}"
-	CustomMustLengthTpl      = "{{.must_length}}"
-	CustomMustMinTpl         = "{{.must_min}}"
-	CustomMustMaxTpl         = "{{.must_max}}"
-	CustomMustLengthRangeTpl = "{{.must_length_range}}"
-	CustomMustValuesTpl      = "{{.must_values}}"
-	CustomMustTimeLayoutTpl  = "{{.must_time_layout}}"
-	CustomMustLessThanTpl    = "{{.must_less_than}}"
-	CustomMustLargeThanTpl   = "{{.must_large_than}}"
+	CustomUnknownParamTpl         = "{{.unknow_param}}"
+	CustomRequireParamTpl         = "{{.require_param}}"
+	CustomRequireNotNullTpl       = "{{.require_not_null}}"
+	CustomForbiddenParamTpl       = "{{.forbidden_param}}"
+	CustomMustNotEmptyTpl         = "{{.must_not_empty}}"
+	CustomMustIntTpl              = "{{.must_int}}"
+	CustomMustInt64Tpl            = "{{.must_int64}}"
+	CustomMustFloat64Tpl          = "{{.must_float64}}"
+	CustomMustBoolTpl             = "{{.must_bool}}"
+	CustomMustFuncTpl             = "{{.must_func}}"
+	CustomMustTypeTpl             = "{{.must_type}}"
+	CustomMustLengthTpl           = "{{.must_length}}"
+	CustomMustMinTpl              = "{{.must_min}}"
+	CustomMustMaxTpl              = "{{.must_max}}"
+	CustomMustGreaterThanValueTpl = "{{.must_greater_than_value}}"
+	CustomMustLessThanValueTpl    = "{{.must_less_than_value}}"
+	CustomMustLengthRangeTpl      = "{{.must_length_range}}"
+	CustomMustValuesTpl           = "{{.must_values}}"
+	CustomMustNotValuesTpl        = "{{.must_not_values}}"
+	CustomMustTimeLayoutTpl       = "{{.must_time_layout}}"
+	CustomMustMatchTpl            = "{{.must_match}}"
+	CustomMustNotMatchTpl         = "{{.must_not_match}}"
+	CustomMustPrefixTpl           = "{{.must_prefix}}"
+	CustomMustSuffixTpl           = "{{.must_suffix}}"
+	CustomMustContainsTpl         = "{{.must_contains}}"
+	CustomMustASCIITpl            = "{{.must_ascii}}"
+	CustomMustAlphanumericTpl     = "{{.must_alphanumeric}}"
+	CustomMustDigitsTpl           = "{{.must_digits}}"
+	CustomMustHanTpl              = "{{.must_han}}"
+	CustomMustNoEmojiTpl          = "{{.must_no_emoji}}"
+	CustomMustNoControlTpl        = "{{.must_no_control}}"
+	CustomMustEmailTpl            = "{{.must_email}}"
+	CustomMustURLTpl              = "{{.must_url}}"
+	CustomMustIPTpl               = "{{.must_ip}}"
+	CustomMustIPv4Tpl             = "{{.must_ipv4}}"
+	CustomMustIPv6Tpl             = "{{.must_ipv6}}"
+	CustomMustCIDRTpl             = "{{.must_cidr}}"
+	CustomMustHostnameTpl         = "{{.must_hostname}}"
+	CustomMustPortTpl             = "{{.must_port}}"
+	CustomMustMACTpl              = "{{.must_mac}}"
+	CustomMustUUIDTpl             = "{{.must_uuid}}"
+	CustomMustHexTpl              = "{{.must_hex}}"
+	CustomMustBase64Tpl           = "{{.must_base64}}"
+	CustomMustSemverTpl           = "{{.must_semver}}"
+	CustomMustMobileCNTpl         = "{{.must_mobile_cn}}"
+	CustomMustIDCardCNTpl         = "{{.must_id_card_cn}}"
+	CustomMustCreditCodeCNTpl     = "{{.must_credit_code_cn}}"
+	CustomMustPostcodeCNTpl       = "{{.must_postcode_cn}}"
+	CustomMustBankCardTpl         = "{{.must_bank_card}}"
+	CustomMustPasswordPolicyTpl   = "{{.must_password_policy}}"
+	CustomMustNotContainWordsTpl  = "{{.must_not_contain_words}}"
+	CustomMustLessThanTpl         = "{{.must_less_than}}"
+	CustomMustLargeThanTpl        = "{{.must_large_than}}"
 )
 
 //错误接口
@@ -42,123 +200,304 @@ func (p *ParamsError) Error() string {
 	return p.Text
 }
 
+//支持errors.Is(err, CodeMustMin)
+func (p *ParamsError) Is(target error) bool {
+	if code, ok := target.(ErrorCode); ok {
+		return p.Code == code
+	}
+	return false
+}
+
+//用于自定义错误码，MustFunc中使用
+func (p *ParamsError) SetCode(code ErrorCode) *ParamsError {
+	p.Code = code
+	return p
+}
+
 //位置参数
 func (p *ParamsError) ErrUnknownParam(cus bool) *ParamsError {
-	if cus {
-		p.Text = CustomUnknownParamTpl
-		return p
-	}
-	p.Text = defaultUnknownParamTpl
-	return p.Tr()
+	return p.build(CodeUnknownParam, cus, CustomUnknownParamTpl, defaultUnknownParamTpl)
 }
 
-//用于自定义参数
+//用于自定义参数，text不会在校验时作为模板渲染，需要时调用Render
 func (p *ParamsError) CustomErrorText(text string) *ParamsError {
 	p.Text = text
+	p.tpl = ""
 	return p
 }
 
 func (p *ParamsError) ErrRequireParam(cus bool) *ParamsError {
-	if cus {
-		p.Text = CustomRequireParamTpl
-		return p
-	}
-	p.Text = defaultRequireParamTpl
-	return p.Tr()
+	return p.build(CodeRequireParam, cus, CustomRequireParamTpl, defaultRequireParamTpl)
 }
 
 func (p *ParamsError) ErrRequireNotNull(cus bool) *ParamsError {
-	if cus {
-		p.Text = CustomRequireNotNullTpl
-		return p
-	}
-	p.Text = defaultRequireNotNullTpl
-	return p.Tr()
+	return p.build(CodeRequireNotNull, cus, CustomRequireNotNullTpl, defaultRequireNotNullTpl)
+}
+
+func (p *ParamsError) ErrForbiddenParam(cus bool) *ParamsError {
+	return p.build(CodeForbiddenParam, cus, CustomForbiddenParamTpl, defaultForbiddenParamTpl)
+}
+
+func (p *ParamsError) ErrMustNotEmpty(cus bool) *ParamsError {
+	return p.build(CodeMustNotEmpty, cus, CustomMustNotEmptyTpl, defaultMustNotEmptyTpl)
+}
+
+func (p *ParamsError) ErrMustInt(cus bool) *ParamsError {
+	return p.build(CodeMustInt, cus, CustomMustIntTpl, defaultMustIntTpl)
+}
+
+func (p *ParamsError) ErrMustInt64(cus bool) *ParamsError {
+	return p.build(CodeMustInt64, cus, CustomMustInt64Tpl, defaultMustInt64Tpl)
+}
+
+func (p *ParamsError) ErrMustFloat64(cus bool) *ParamsError {
+	return p.build(CodeMustFloat64, cus, CustomMustFloat64Tpl, defaultMustFloat64Tpl)
+}
+
+func (p *ParamsError) ErrMustBool(cus bool) *ParamsError {
+	return p.build(CodeMustBool, cus, CustomMustBoolTpl, defaultMustBoolTpl)
+}
+
+func (p *ParamsError) ErrMustFunc(cus bool) *ParamsError {
+	return p.build(CodeMustFunc, cus, CustomMustFuncTpl, defaultMustFuncTpl)
+}
+
+func (p *ParamsError) ErrMustType(cus bool) *ParamsError {
+	return p.build(CodeMustType, cus, CustomMustTypeTpl, defaultMustTypeTpl)
 }
 
 func (p *ParamsError) ErrMustLength(cus bool) *ParamsError {
-	if cus {
-		p.Text = CustomMustLengthTpl
-		return p
-	}
-	p.Text = defaultMustLengthTpl
-	return p.Tr()
+	return p.build(CodeMustLength, cus, CustomMustLengthTpl, defaultMustLengthTpl)
 }
 
 func (p *ParamsError) ErrMustMin(cus bool) *ParamsError {
-	if cus {
-		p.Text = CustomMustMinTpl
-		return p
-	}
-	p.Text = defaultMustMinTpl
-	return p.Tr()
+	return p.build(CodeMustMin, cus, CustomMustMinTpl, defaultMustMinTpl)
 }
 
 func (p *ParamsError) ErrMustMax(cus bool) *ParamsError {
-	if cus {
-		p.Text = CustomMustMaxTpl
-		return p
-	}
-	p.Text = defaultMustMaxTpl
-	return p.Tr()
+	return p.build(CodeMustMax, cus, CustomMustMaxTpl, defaultMustMaxTpl)
+}
+
+func (p *ParamsError) ErrMustGreaterThanValue(cus bool) *ParamsError {
+	return p.build(CodeMustGreaterThanValue, cus, CustomMustGreaterThanValueTpl, defaultMustGreaterThanValueTpl)
+}
+
+func (p *ParamsError) ErrMustLessThanValue(cus bool) *ParamsError {
+	return p.build(CodeMustLessThanValue, cus, CustomMustLessThanValueTpl, defaultMustLessThanValueTpl)
 }
 
 func (p *ParamsError) ErrMustLengthRange(cus bool) *ParamsError {
-	if cus {
-		p.Text = CustomMustLengthRangeTpl
-		return p
-	}
-	p.Text = defaultMustLengthRangeTpl
-	return p.Tr()
+	return p.build(CodeMustLengthRange, cus, CustomMustLengthRangeTpl, defaultMustLengthRangeTpl)
 }
 
 func (p *ParamsError) ErrMustValues(cus bool) *ParamsError {
-	if cus {
-		p.Text = CustomMustValuesTpl
-		return p
-	}
-	p.Text = defaultMustValuesTpl
-	return p.Tr()
+	return p.build(CodeMustValues, cus, CustomMustValuesTpl, defaultMustValuesTpl)
+}
+
+func (p *ParamsError) ErrMustNotValues(cus bool) *ParamsError {
+	return p.build(CodeMustNotValues, cus, CustomMustNotValuesTpl, defaultMustNotValuesTpl)
 }
 
 func (p *ParamsError) ErrMustTimeLayout(cus bool) *ParamsError {
+	return p.build(CodeMustTimeLayout, cus, CustomMustTimeLayoutTpl, defaultMustTimeLayoutTpl)
+}
+
+func (p *ParamsError) ErrMustMatch(cus bool) *ParamsError {
+	return p.build(CodeMustMatch, cus, CustomMustMatchTpl, defaultMustMatchTpl)
+}
+
+func (p *ParamsError) ErrMustNotMatch(cus bool) *ParamsError {
+	return p.build(CodeMustNotMatch, cus, CustomMustNotMatchTpl, defaultMustNotMatchTpl)
+}
+
+func (p *ParamsError) ErrMustPrefix(cus bool) *ParamsError {
+	return p.build(CodeMustPrefix, cus, CustomMustPrefixTpl, defaultMustPrefixTpl)
+}
+
+func (p *ParamsError) ErrMustSuffix(cus bool) *ParamsError {
+	return p.build(CodeMustSuffix, cus, CustomMustSuffixTpl, defaultMustSuffixTpl)
+}
+
+func (p *ParamsError) ErrMustContains(cus bool) *ParamsError {
+	return p.build(CodeMustContains, cus, CustomMustContainsTpl, defaultMustContainsTpl)
+}
+
+func (p *ParamsError) ErrMustASCII(cus bool) *ParamsError {
+	return p.build(CodeMustASCII, cus, CustomMustASCIITpl, defaultMustASCIITpl)
+}
+
+func (p *ParamsError) ErrMustAlphanumeric(cus bool) *ParamsError {
+	return p.build(CodeMustAlphanumeric, cus, CustomMustAlphanumericTpl, defaultMustAlphanumericTpl)
+}
+
+func (p *ParamsError) ErrMustDigits(cus bool) *ParamsError {
+	return p.build(CodeMustDigits, cus, CustomMustDigitsTpl, defaultMustDigitsTpl)
+}
+
+func (p *ParamsError) ErrMustHan(cus bool) *ParamsError {
+	return p.build(CodeMustHan, cus, CustomMustHanTpl, defaultMustHanTpl)
+}
+
+func (p *ParamsError) ErrMustNoEmoji(cus bool) *ParamsError {
+	return p.build(CodeMustNoEmoji, cus, CustomMustNoEmojiTpl, defaultMustNoEmojiTpl)
+}
+
+func (p *ParamsError) ErrMustNoControl(cus bool) *ParamsError {
+	return p.build(CodeMustNoControl, cus, CustomMustNoControlTpl, defaultMustNoControlTpl)
+}
+
+func (p *ParamsError) ErrMustEmail(cus bool) *ParamsError {
+	return p.build(CodeMustEmail, cus, CustomMustEmailTpl, defaultMustEmailTpl)
+}
+
+func (p *ParamsError) ErrMustURL(cus bool) *ParamsError {
+	return p.build(CodeMustURL, cus, CustomMustURLTpl, defaultMustURLTpl)
+}
+
+func (p *ParamsError) ErrMustIP(cus bool) *ParamsError {
+	return p.build(CodeMustIP, cus, CustomMustIPTpl, defaultMustIPTpl)
+}
+
+func (p *ParamsError) ErrMustIPv4(cus bool) *ParamsError {
+	return p.build(CodeMustIPv4, cus, CustomMustIPv4Tpl, defaultMustIPv4Tpl)
+}
+
+func (p *ParamsError) ErrMustIPv6(cus bool) *ParamsError {
+	return p.build(CodeMustIPv6, cus, CustomMustIPv6Tpl, defaultMustIPv6Tpl)
+}
+
+func (p *ParamsError) ErrMustCIDR(cus bool) *ParamsError {
+	return p.build(CodeMustCIDR, cus, CustomMustCIDRTpl, defaultMustCIDRTpl)
+}
+
+func (p *ParamsError) ErrMustHostname(cus bool) *ParamsError {
+	return p.build(CodeMustHostname, cus, CustomMustHostnameTpl, defaultMustHostnameTpl)
+}
+
+func (p *ParamsError) ErrMustPort(cus bool) *ParamsError {
+	return p.build(CodeMustPort, cus, CustomMustPortTpl, defaultMustPortTpl)
+}
+
+func (p *ParamsError) ErrMustMAC(cus bool) *ParamsError {
+	return p.build(CodeMustMAC, cus, CustomMustMACTpl, defaultMustMACTpl)
+}
+
+func (p *ParamsError) ErrMustUUID(cus bool) *ParamsError {
+	return p.build(CodeMustUUID, cus, CustomMustUUIDTpl, defaultMustUUIDTpl)
+}
+
+func (p *ParamsError) ErrMustHex(cus bool) *ParamsError {
+	return p.build(CodeMustHex, cus, CustomMustHexTpl, defaultMustHexTpl)
+}
+
+func (p *ParamsError) ErrMustBase64(cus bool) *ParamsError {
+	return p.build(CodeMustBase64, cus, CustomMustBase64Tpl, defaultMustBase64Tpl)
+}
+
+func (p *ParamsError) ErrMustSemver(cus bool) *ParamsError {
+	return p.build(CodeMustSemver, cus, CustomMustSemverTpl, defaultMustSemverTpl)
+}
+
+func (p *ParamsError) ErrMustMobileCN(cus bool) *ParamsError {
+	return p.build(CodeMustMobileCN, cus, CustomMustMobileCNTpl, defaultMustMobileCNTpl)
+}
+
+func (p *ParamsError) ErrMustIDCardCN(cus bool) *ParamsError {
+	return p.build(CodeMustIDCardCN, cus, CustomMustIDCardCNTpl, defaultMustIDCardCNTpl)
+}
+
+func (p *ParamsError) ErrMustCreditCodeCN(cus bool) *ParamsError {
+	return p.build(CodeMustCreditCodeCN, cus, CustomMustCreditCodeCNTpl, defaultMustCreditCodeCNTpl)
+}
+
+func (p *ParamsError) ErrMustPostcodeCN(cus bool) *ParamsError {
+	return p.build(CodeMustPostcodeCN, cus, CustomMustPostcodeCNTpl, defaultMustPostcodeCNTpl)
+}
+
+func (p *ParamsError) ErrMustBankCard(cus bool) *ParamsError {
+	return p.build(CodeMustBankCard, cus, CustomMustBankCardTpl, defaultMustBankCardTpl)
+}
+
+func (p *ParamsError) ErrMustPasswordPolicy(cus bool) *ParamsError {
+	return p.build(CodeMustPasswordPolicy, cus, CustomMustPasswordPolicyTpl, defaultMustPasswordPolicyTpl)
+}
+
+func (p *ParamsError) ErrMustNotContainWords(cus bool) *ParamsError {
+	return p.build(CodeMustNotContainWords, cus, CustomMustNotContainWordsTpl, defaultMustNotContainWordsTpl)
+}
+
+func (p *ParamsError) ErrMustLessThan(cus bool) *ParamsError {
+	return p.build(CodeMustLessThan, cus, CustomMustLessThanTpl, defaultMustLessThanTpl)
+}
+
+func (p *ParamsError) ErrMustLargeThan(cus bool) *ParamsError {
+	return p.build(CodeMustLargeThan, cus, CustomMustLargeThanTpl, defaultMustLargeThanTpl)
+}
+
+func (p *ParamsError) build(code ErrorCode, cus bool, custom, def string) *ParamsError {
+	p.Code = code
 	if cus {
-		p.Text = CustomMustTimeLayoutTpl
+		p.Text = custom
 		return p
 	}
-	p.Text = defaultMustTimeLayoutTpl
-	return p.Tr()
+	p.Text = def
+	p.tpl = def
+	return p.trBuiltin()
 }
 
-func (p *ParamsError) ErrMustLessThan(cus bool) *ParamsError {
-	if cus {
-		p.Text = CustomMustLessThanTpl
+//设置参数显示名，使用默认模板时重新渲染错误信息
+func (p *ParamsError) SetLabel(label string) *ParamsError {
+	if label == "" || label == p.Label {
 		return p
 	}
-	p.Text = defaultMustLessThanTpl
-	return p.Tr()
+	p.Label = label
+	return p.trBuiltin()
 }
 
-func (p *ParamsError) ErrMustLargeThan(cus bool) *ParamsError {
-	if cus {
-		p.Text = CustomMustLargeThanTpl
+//只渲染内置模板，自定义的错误信息可能包含参数值，不作为模板渲染
+func (p *ParamsError) trBuiltin() *ParamsError {
+	if p.tpl == "" {
 		return p
 	}
-	p.Text = defaultMustLargeThanTpl
-	return p.Tr()
+	if err := p.Render(); err != nil {
+		p.Text = fmt.Sprintf("%s: %s", p.Code, err.Error())
+	}
+	return p
 }
 
-func (p *ParamsError) Tr() *ParamsError {
-	var buff = make([]byte, 0)
-	b := bytes.NewBuffer(buff)
-	tmpl(b, p.Text, p)
+//渲染错误信息模板，CustomErrorText设置的错误信息也作为模板渲染，模板有误时返回error，错误信息不变
+func (p *ParamsError) Render() error {
+	if p.Label == "" {
+		p.Label = p.Key
+	}
+	var t *texttemplate.Template
+	var err error
+	if p.tpl != "" {
+		t, err = builtinTmpl(p.tpl)
+	} else {
+		t, err = parseTextTmpl(p.Text)
+	}
+	if err != nil {
+		return err
+	}
+	var b bytes.Buffer
+	if err := t.Execute(&b, p); err != nil {
+		return err
+	}
 	p.Text = b.String()
+	return nil
+}
+
+//同Render，便于链式调用，模板有误时错误信息保持原样，需要错误时使用Render
+func (p *ParamsError) Tr() *ParamsError {
+	p.Render()
 	return p
 }
 
 func NewParamsError(k string, v interface{}) *ParamsError {
 	pErr := new(ParamsError)
 	pErr.Key = k
+	pErr.Label = k
 	pErr.Value = v
 	return pErr
 }
@@ -168,3 +507,34 @@ func NewTextError(text string) *ParamsError {
 	pErr.Text = text
 	return pErr
 }
+
+func (e ParamsErrors) Error() string {
+	texts := make([]string, 0, len(e))
+	for _, pErr := range e {
+		texts = append(texts, pErr.Error())
+	}
+	return strings.Join(texts, "; ")
+}
+
+//支持errors.Is对每个错误进行匹配
+func (e ParamsErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, pErr := range e {
+		errs = append(errs, pErr)
+	}
+	return errs
+}
+
+//将error展开为参数错误列表，非ParamsError的错误转为无错误码的ParamsError
+func AsParamsErrors(err error) ParamsErrors {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case ParamsErrors:
+		return e
+	case *ParamsError:
+		return ParamsErrors{e}
+	default:
+		return ParamsErrors{NewTextError(err.Error())}
+	}
+}
@@ -0,0 +1,251 @@
+package validator
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+//内置格式校验，store为true时解析后的值会保存，并绑定到类型匹配的结构体字段
+//name用于JSON Schema和Compile的错误信息，label为规则文档中显示的名称，为空时使用name
+type format struct {
+	name  string
+	label string
+	parse func(string) (interface{}, bool)
+	store bool
+	err   func(*ParamsError, bool) *ParamsError
+}
+
+var semverRegexp = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+var (
+	emailFormat = &format{
+		name: "email",
+		parse: func(s string) (interface{}, bool) {
+			addr, err := mail.ParseAddress(s)
+			return addr, err == nil && addr.Address == s
+		},
+		err: (*ParamsError).ErrMustEmail,
+	}
+	urlFormat = &format{
+		name: "url",
+		parse: func(s string) (interface{}, bool) {
+			u, err := url.Parse(s)
+			return u, err == nil && u.Scheme != "" && u.Host != ""
+		},
+		store: true,
+		err:   (*ParamsError).ErrMustURL,
+	}
+	ipFormat = &format{
+		name: "ip",
+		parse: func(s string) (interface{}, bool) {
+			ip := net.ParseIP(s)
+			return ip, ip != nil
+		},
+		store: true,
+		err:   (*ParamsError).ErrMustIP,
+	}
+	ipv4Format = &format{
+		name: "ipv4",
+		parse: func(s string) (interface{}, bool) {
+			ip := net.ParseIP(s)
+			return ip, ip != nil && ip.To4() != nil && !strings.Contains(s, ":")
+		},
+		store: true,
+		err:   (*ParamsError).ErrMustIPv4,
+	}
+	ipv6Format = &format{
+		name: "ipv6",
+		parse: func(s string) (interface{}, bool) {
+			ip := net.ParseIP(s)
+			return ip, ip != nil && strings.Contains(s, ":")
+		},
+		store: true,
+		err:   (*ParamsError).ErrMustIPv6,
+	}
+	cidrFormat = &format{
+		name: "cidr",
+		parse: func(s string) (interface{}, bool) {
+			_, ipNet, err := net.ParseCIDR(s)
+			return ipNet, err == nil
+		},
+		store: true,
+		err:   (*ParamsError).ErrMustCIDR,
+	}
+	hostnameFormat = &format{
+		name:  "hostname",
+		parse: func(s string) (interface{}, bool) { return s, isHostname(s) },
+		err:   (*ParamsError).ErrMustHostname,
+	}
+	portFormat = &format{
+		name: "port",
+		parse: func(s string) (interface{}, bool) {
+			port, err := strconv.Atoi(s)
+			return port, err == nil && port > 0 && port <= 65535
+		},
+		err: (*ParamsError).ErrMustPort,
+	}
+	macFormat = &format{
+		name: "mac",
+		parse: func(s string) (interface{}, bool) {
+			mac, err := net.ParseMAC(s)
+			return mac, err == nil
+		},
+		store: true,
+		err:   (*ParamsError).ErrMustMAC,
+	}
+	uuidFormat = &format{
+		name:  "uuid",
+		parse: func(s string) (interface{}, bool) { return s, isUUID(s) },
+		err:   (*ParamsError).ErrMustUUID,
+	}
+	hexFormat = &format{
+		name: "hex",
+		parse: func(s string) (interface{}, bool) {
+			b, err := hex.DecodeString(s)
+			return b, err == nil
+		},
+		err: (*ParamsError).ErrMustHex,
+	}
+	base64Format = &format{
+		name: "base64",
+		parse: func(s string) (interface{}, bool) {
+			b, err := base64.StdEncoding.DecodeString(s)
+			return b, err == nil
+		},
+		err: (*ParamsError).ErrMustBase64,
+	}
+	semverFormat = &format{
+		name:  "semver",
+		parse: func(s string) (interface{}, bool) { return s, semverRegexp.MatchString(s) },
+		err:   (*ParamsError).ErrMustSemver,
+	}
+)
+
+//RFC 1123主机名
+func isHostname(s string) bool {
+	s = strings.TrimSuffix(s, ".")
+	if s == "" || len(s) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(s, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, c := range label {
+			if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func isUUID(s string) bool {
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return false
+	}
+	_, err := hex.DecodeString(s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:36])
+	return err == nil
+}
+
+//校验格式，需要保存的解析值写入parsedMap
+func (v *Validator) formatCheck(key, value string, store bool) error {
+	f, ok := v.formatMap[key]
+	if !ok {
+		return nil
+	}
+	parsed, ok := f.parse(value)
+	if !ok {
+		return f.err(NewParamsError(key, value), v.CustomError)
+	}
+	if store && f.store {
+		v.parsedMap[key] = parsed
+	}
+	return nil
+}
+
+//每个参数只能声明一种格式
+func (r *ruleSet) mustFormat(name string, f *format) RuleSet {
+	r.valid.own()
+	if r.setError != nil {
+		return r
+	}
+	if r.paramName == "" {
+		panic("unknown param name when set " + name)
+	}
+	if declared, ok := r.valid.formatMap[r.paramName]; ok && declared != f {
+		return r.fail(fmt.Errorf("%s conflicts with format %s declared before", name, declared.name))
+	}
+	r.valid.formatMap[r.paramName] = f
+	doc := f.label
+	if doc == "" {
+		doc = f.name
+	}
+	r.addRule(&rule{doc: "格式" + doc, name: name, args: []interface{}{f}})
+	return r
+}
+
+func (r *ruleSet) MustEmail() RuleSet {
+	return r.mustFormat("MustEmail", emailFormat)
+}
+
+//解析后的*url.URL可绑定到结构体字段
+func (r *ruleSet) MustURL() RuleSet {
+	return r.mustFormat("MustURL", urlFormat)
+}
+
+//解析后的net.IP可绑定到结构体字段
+func (r *ruleSet) MustIP() RuleSet {
+	return r.mustFormat("MustIP", ipFormat)
+}
+
+func (r *ruleSet) MustIPv4() RuleSet {
+	return r.mustFormat("MustIPv4", ipv4Format)
+}
+
+func (r *ruleSet) MustIPv6() RuleSet {
+	return r.mustFormat("MustIPv6", ipv6Format)
+}
+
+//解析后的*net.IPNet可绑定到结构体字段
+func (r *ruleSet) MustCIDR() RuleSet {
+	return r.mustFormat("MustCIDR", cidrFormat)
+}
+
+func (r *ruleSet) MustHostname() RuleSet {
+	return r.mustFormat("MustHostname", hostnameFormat)
+}
+
+func (r *ruleSet) MustPort() RuleSet {
+	return r.mustFormat("MustPort", portFormat)
+}
+
+//解析后的net.HardwareAddr可绑定到结构体字段
+func (r *ruleSet) MustMAC() RuleSet {
+	return r.mustFormat("MustMAC", macFormat)
+}
+
+func (r *ruleSet) MustUUID() RuleSet {
+	return r.mustFormat("MustUUID", uuidFormat)
+}
+
+func (r *ruleSet) MustHex() RuleSet {
+	return r.mustFormat("MustHex", hexFormat)
+}
+
+func (r *ruleSet) MustBase64() RuleSet {
+	return r.mustFormat("MustBase64", base64Format)
+}
+
+func (r *ruleSet) MustSemver() RuleSet {
+	return r.mustFormat("MustSemver", semverFormat)
+}
@@ -0,0 +1,220 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	paramGroupsMu sync.RWMutex
+	paramGroups   = make(map[string]*Validator)
+)
+
+//注册可复用的参数组，如分页、排序、鉴权参数，保存的是group的副本
+func RegisterParamGroup(name string, group *Validator) {
+	g := group.Clone()
+	g.Name = name
+	paramGroupsMu.Lock()
+	defer paramGroupsMu.Unlock()
+	paramGroups[name] = g
+}
+
+//引入已注册的参数组，参数名加上prefix
+func (v *Validator) UseGroup(name, prefix string) error {
+	paramGroupsMu.RLock()
+	group, ok := paramGroups[name]
+	paramGroupsMu.RUnlock()
+	if !ok {
+		return fmt.Errorf("param group %s not registered", name)
+	}
+	return v.Include(group, prefix)
+}
+
+//合并other中声明的参数，参数名加上prefix，同名参数冲突时不做任何修改并返回错误
+func (v *Validator) Include(other *Validator, prefix string) error {
+	var names, conflicts []string
+	for name := range other.ApiParams {
+		if v.declared(prefix + name) {
+			conflicts = append(conflicts, prefix+name)
+		}
+		names = append(names, name)
+	}
+	for _, c := range other.computed {
+		if v.declared(prefix + c.name) {
+			conflicts = append(conflicts, prefix+c.name)
+		}
+	}
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return fmt.Errorf("include %s: params already declared: %s", other.sourceName(), strings.Join(conflicts, ", "))
+	}
+	v.own()
+	source := other.sourceName()
+	rename := func(name string) string {
+		if other.declared(name) {
+			return prefix + name
+		}
+		return name
+	}
+	for _, name := range names {
+		newName := prefix + name
+		p := *other.ApiParams[name]
+		if p.Source == "" {
+			p.Source = source
+		} else {
+			p.Source = source + "/" + p.Source
+		}
+		p.Rules = renameRules(other.ApiParams[name].Rules, rename)
+		if p.Labels != nil {
+			labels := make(map[string]string, len(p.Labels))
+			for locale, label := range p.Labels {
+				labels[locale] = label
+			}
+			p.Labels = labels
+		}
+		p.Transforms = append([]string(nil), p.Transforms...)
+		v.ApiParams[newName] = &p
+		v.ruleMap[newName] = renameRules(other.ruleMap[name], rename)
+		if kind, ok := other.typeMap[name]; ok {
+			v.typeMap[newName] = kind
+		}
+		if kind, ok := other.elemTypeMap[name]; ok {
+			v.elemTypeMap[newName] = kind
+		}
+		if t, ok := other.customTypeMap[name]; ok {
+			v.customTypeMap[newName] = t
+		}
+		if sep, ok := other.separatorMap[name]; ok {
+			v.separatorMap[newName] = sep
+		}
+		if layout, ok := other.timeLayoutMap[name]; ok {
+			v.timeLayoutMap[newName] = layout
+		}
+		if f, ok := other.formatMap[name]; ok {
+			v.formatMap[newName] = f
+		}
+		if ts, ok := other.transformMap[name]; ok {
+			v.transformMap[newName] = append([]transformer(nil), ts...)
+		}
+		if value, ok := other.defaultValueMap[name]; ok {
+			v.defaultValueMap[newName] = value
+		}
+		if other.forbidParams[name] {
+			v.forbidParams[newName] = true
+		}
+		if err, ok := other.typeErrMap[name]; ok {
+			if v.typeErrMap == nil {
+				v.typeErrMap = make(map[string]error)
+			}
+			v.typeErrMap[newName] = err
+		}
+	}
+	//DefaultFunc和计算参数按原参数名读取其他参数
+	for _, d := range other.defaultFuncs {
+		d := d
+		f := d.f
+		if prefix != "" {
+			f = func(g ValueGetter) interface{} {
+				return d.f(prefixGetter{ValueGetter: g, rename: rename})
+			}
+		}
+		v.setDefaultFunc(prefix+d.name, f)
+	}
+	for _, c := range other.computed {
+		c := c
+		f := c.f
+		if prefix != "" {
+			f = func(g ValueGetter) (interface{}, error) {
+				return c.f(prefixGetter{ValueGetter: g, rename: rename})
+			}
+		}
+		v.computed = append(v.computed, computedParam{name: prefix + c.name, kind: c.kind, f: f})
+		v.typeMap[prefix+c.name] = c.kind
+	}
+	for _, name := range other.requireParams {
+		v.requireParams = append(v.requireParams, prefix+name)
+	}
+	for _, name := range other.requireUrlParams {
+		v.requireUrlParams = append(v.requireUrlParams, prefix+name)
+	}
+	if v.splitChar == "" {
+		v.splitChar = other.splitChar
+	}
+	v.declErrors = append(v.declErrors, other.declErrors...)
+	return nil
+}
+
+//参数或计算参数是否已声明
+func (v *Validator) declared(name string) bool {
+	if _, ok := v.ApiParams[name]; ok {
+		return true
+	}
+	for _, c := range v.computed {
+		if c.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+//将原参数名转换为加上前缀后的参数名
+type prefixGetter struct {
+	ValueGetter
+	rename func(string) string
+}
+
+func (g prefixGetter) Value(name string) (interface{}, bool) {
+	return g.ValueGetter.Value(g.rename(name))
+}
+
+func (g prefixGetter) Int(name string) int {
+	return g.ValueGetter.Int(g.rename(name))
+}
+
+func (g prefixGetter) Int64(name string) int64 {
+	return g.ValueGetter.Int64(g.rename(name))
+}
+
+func (g prefixGetter) Float64(name string) float64 {
+	return g.ValueGetter.Float64(g.rename(name))
+}
+
+func (g prefixGetter) Bool(name string) bool {
+	return g.ValueGetter.Bool(g.rename(name))
+}
+
+func (g prefixGetter) String(name string) string {
+	return g.ValueGetter.String(g.rename(name))
+}
+
+func (g prefixGetter) Slice(name string) []interface{} {
+	return g.ValueGetter.Slice(g.rename(name))
+}
+
+func (g prefixGetter) Presence(name string) Presence {
+	return g.ValueGetter.Presence(g.rename(name))
+}
+
+func (v *Validator) sourceName() string {
+	if v.Name != "" {
+		return v.Name
+	}
+	return "include"
+}
+
+//复制规则，引用其他参数的规则改为新的参数名
+func renameRules(rules []rule, rename func(string) string) []rule {
+	copied := make([]rule, 0, len(rules))
+	for _, rl := range rules {
+		if rl.fieldArg {
+			field := rl.args[0].(string)
+			newField := rename(field)
+			rl.args = append([]interface{}{newField}, rl.args[1:]...)
+			rl.doc = strings.TrimSuffix(rl.doc, field) + newField
+		}
+		copied = append(copied, rl)
+	}
+	return copied
+}
@@ -0,0 +1,510 @@
+//validgen使用的代码生成，根据结构体的valid和validate标签生成不使用反射的BindFrom方法和Validator构造函数
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	validTag    = "valid"
+	validateTag = "validate"
+	importPath  = "github.com/go-wyvern/validator"
+)
+
+type Config struct {
+	//需要生成的结构体类型名
+	Types []string
+	//引用validator包的前缀，生成到validator包内部时为空
+	Qualifier string
+	//生成命令，写入文件头部
+	Command string
+}
+
+//字段类型的分类，决定使用的Set函数和参数类型声明
+type category int
+
+const (
+	catOther category = iota
+	catSigned
+	catInt64
+	catUnsigned
+	catFloat
+	catBool
+	catString
+	catStruct
+)
+
+var builtinCategories = map[string]category{
+	"int": catSigned, "int8": catSigned, "int16": catSigned, "int32": catSigned, "rune": catSigned, "int64": catInt64,
+	"uint": catUnsigned, "uint8": catUnsigned, "uint16": catUnsigned, "uint32": catUnsigned, "uint64": catUnsigned, "byte": catUnsigned,
+	"float32": catFloat, "float64": catFloat,
+	"bool": catBool, "string": catString,
+}
+
+var setFuncs = map[category]string{
+	catOther:    "SetAny",
+	catSigned:   "SetInt",
+	catInt64:    "SetInt",
+	catUnsigned: "SetUint",
+	catFloat:    "SetFloat",
+	catBool:     "SetBool",
+	catString:   "SetString",
+	catStruct:   "SetAny",
+}
+
+var elemKinds = map[category]string{
+	catSigned:   "reflect.Int",
+	catInt64:    "reflect.Int64",
+	catUnsigned: "reflect.Int",
+	catFloat:    "reflect.Float64",
+	catBool:     "reflect.Bool",
+	catString:   "reflect.String",
+}
+
+//validate标签中不带参数的规则
+var flagRules = map[string]string{
+	"email": "MustEmail", "url": "MustURL", "ip": "MustIP", "ipv4": "MustIPv4", "ipv6": "MustIPv6",
+	"cidr": "MustCIDR", "hostname": "MustHostname", "port": "MustPort", "mac": "MustMAC", "uuid": "MustUUID",
+	"hex": "MustHex", "base64": "MustBase64", "semver": "MustSemver",
+	"mobile_cn": "MustMobileCN", "idcard_cn": "MustIDCardCN", "credit_code_cn": "MustCreditCodeCN",
+	"postcode_cn": "MustPostcodeCN", "bank_card": "MustBankCard",
+	"ascii": "MustASCII", "alphanumeric": "MustAlphanumeric", "digits": "MustDigits", "han": "MustHan",
+	"trim": "Trim", "lower": "ToLower", "upper": "ToUpper",
+	"allow_empty": "AllowEmpty", "check_empty": "CheckEmpty", "not_empty": "NotEmptyIfPresent", "sensitive": "Sensitive",
+}
+
+//validate标签中带参数的规则，值为规则方法和参数是否为字符串
+var argRules = map[string]struct {
+	method string
+	quote  bool
+}{
+	"min": {"MustMin", false}, "max": {"MustMax", false}, "length": {"MustLength", false},
+	"layout": {"MustTimeLayout", true}, "match": {"MustMatch", true}, "prefix": {"MustPrefix", true},
+	"suffix": {"MustSuffix", true}, "contains": {"MustContains", true},
+	"label": {"Label", true}, "desc": {"Description", true}, "type": {"MustType", true},
+	"less_than": {"MustLessThan", true}, "large_than": {"MustLargeThan", true},
+}
+
+type generator struct {
+	cfg     Config
+	pkg     string
+	types   map[string]*ast.TypeSpec
+	texts   map[string]bool
+	pending []string
+	done    map[string]bool
+	buf     bytes.Buffer
+	reflect bool
+}
+
+//为files中声明的cfg.Types生成代码，files需包含所有被引用的本包类型
+func Generate(files []*ast.File, cfg Config) ([]byte, error) {
+	g := &generator{cfg: cfg, types: map[string]*ast.TypeSpec{}, texts: map[string]bool{}, done: map[string]bool{}}
+	for _, f := range files {
+		if g.pkg == "" {
+			g.pkg = f.Name.Name
+		}
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					if ts, ok := spec.(*ast.TypeSpec); ok {
+						g.types[ts.Name.Name] = ts
+					}
+				}
+			case *ast.FuncDecl:
+				if d.Name.Name == "UnmarshalText" && d.Recv != nil && len(d.Recv.List) == 1 {
+					g.texts[receiverName(d.Recv.List[0].Type)] = true
+				}
+			}
+		}
+	}
+
+	var body bytes.Buffer
+	for _, name := range cfg.Types {
+		ts, ok := g.types[name]
+		if !ok {
+			return nil, fmt.Errorf("type %s not found", name)
+		}
+		st, ok := ts.Type.(*ast.StructType)
+		if !ok {
+			return nil, fmt.Errorf("type %s is not a struct", name)
+		}
+		g.buf.Reset()
+		if err := g.constructor(name, st); err != nil {
+			return nil, err
+		}
+		body.Write(g.buf.Bytes())
+		g.pending = append(g.pending, name)
+	}
+	for len(g.pending) > 0 {
+		name := g.pending[0]
+		g.pending = g.pending[1:]
+		if g.done[name] {
+			continue
+		}
+		g.done[name] = true
+		g.buf.Reset()
+		if err := g.binder(name, g.types[name].Type.(*ast.StructType)); err != nil {
+			return nil, err
+		}
+		body.Write(g.buf.Bytes())
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by %s. DO NOT EDIT.\n\npackage %s\n\n", cfg.Command, g.pkg)
+	var imports []string
+	if g.reflect {
+		imports = append(imports, strconv.Quote("reflect"))
+	}
+	if cfg.Qualifier != "" {
+		imports = append(imports, strconv.Quote(importPath))
+	}
+	switch len(imports) {
+	case 0:
+	case 1:
+		fmt.Fprintf(&out, "import %s\n\n", imports[0])
+	default:
+		fmt.Fprintf(&out, "import (\n%s\n)\n\n", strings.Join(imports, "\n\n"))
+	}
+	out.Write(body.Bytes())
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %v", err)
+	}
+	return src, nil
+}
+
+func receiverName(expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if id, ok := expr.(*ast.Ident); ok {
+		return id.Name
+	}
+	return ""
+}
+
+//返回类型的分类，本包的命名类型按底层类型分类，实现了TextUnmarshaler的命名类型为catOther
+func (g *generator) category(expr ast.Expr) category {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if cat, ok := builtinCategories[t.Name]; ok {
+			return cat
+		}
+		ts, ok := g.types[t.Name]
+		if !ok || g.texts[t.Name] {
+			return catOther
+		}
+		if _, ok := ts.Type.(*ast.StructType); ok {
+			return catStruct
+		}
+		return g.category(ts.Type)
+	case *ast.ParenExpr:
+		return g.category(t.X)
+	}
+	return catOther
+}
+
+//字段的绑定形式：T、*T、[]T或[]*T
+func splitField(expr ast.Expr) (elem ast.Expr, ptr, slice bool) {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		return star.X, true, false
+	}
+	if arr, ok := expr.(*ast.ArrayType); ok && arr.Len == nil {
+		if star, ok := arr.Elt.(*ast.StarExpr); ok {
+			return star.X, true, true
+		}
+		return arr.Elt, false, true
+	}
+	return expr, false, false
+}
+
+type tagField struct {
+	field *ast.Field
+	name  string
+	param string
+	tag   reflect.StructTag
+}
+
+//遍历结构体字段，跳过未导出的非嵌入字段和标签为"-"的字段
+func fields(st *ast.StructType) []tagField {
+	var out []tagField
+	for _, f := range st.Fields.List {
+		var tag reflect.StructTag
+		if f.Tag != nil {
+			s, _ := strconv.Unquote(f.Tag.Value)
+			tag = reflect.StructTag(s)
+		}
+		param, _ := tag.Lookup(validTag)
+		if param == "-" {
+			continue
+		}
+		names := f.Names
+		if len(names) == 0 {
+			elem, _, _ := splitField(f.Type)
+			if sel, ok := elem.(*ast.SelectorExpr); ok {
+				elem = sel.Sel
+			}
+			if id, ok := elem.(*ast.Ident); ok {
+				names = []*ast.Ident{id}
+			}
+		}
+		for _, n := range names {
+			if !n.IsExported() && len(f.Names) > 0 {
+				continue
+			}
+			out = append(out, tagField{field: f, name: n.Name, param: param, tag: tag})
+		}
+	}
+	return out
+}
+
+func (g *generator) q(name string) string {
+	return g.cfg.Qualifier + name
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+//生成Validator构造函数，嵌套结构体的参数一并声明
+func (g *generator) constructor(name string, st *ast.StructType) error {
+	g.printf("//根据%s的标签声明参数的Validator\n", name)
+	g.printf("func New%sValidator() *%s {\n", name, g.q("Validator"))
+	g.printf("v := %s()\n", g.q("NewValidator"))
+	if err := g.declare(st, map[string]bool{}, map[string]bool{name: true}); err != nil {
+		return fmt.Errorf("%s: %v", name, err)
+	}
+	g.printf("return v\n}\n\n")
+	return nil
+}
+
+func (g *generator) declare(st *ast.StructType, seen, visiting map[string]bool) error {
+	for _, f := range fields(st) {
+		elem, _, slice := splitField(f.field.Type)
+		if f.param == "" {
+			nested, typeName := g.nestedStruct(f.field.Type)
+			if nested == nil || visiting[typeName] {
+				continue
+			}
+			if typeName != "" {
+				visiting[typeName] = true
+			}
+			if err := g.declare(nested, seen, visiting); err != nil {
+				return err
+			}
+			delete(visiting, typeName)
+			continue
+		}
+		if seen[f.param] {
+			continue
+		}
+		seen[f.param] = true
+		if err := g.declareParam(f, g.category(elem), slice); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *generator) declareParam(f tagField, cat category, slice bool) error {
+	var def, sep string
+	var calls []string
+	if opts := f.tag.Get(validateTag); opts != "" {
+		options, err := splitOptions(opts)
+		if err != nil {
+			return fmt.Errorf("field %s: %v", f.name, err)
+		}
+		for _, opt := range options {
+			key, value, hasValue := strings.Cut(strings.TrimSpace(opt), "=")
+			switch {
+			case key == "require" && !hasValue:
+				calls = append(calls, "Require(true)")
+			case key == "default" && hasValue:
+				def = ", " + strconv.Quote(value)
+			case key == "sep" && hasValue:
+				sep = value
+			case flagRules[key] != "" && !hasValue:
+				calls = append(calls, flagRules[key]+"()")
+			case argRules[key].method != "" && hasValue:
+				rule := argRules[key]
+				if rule.quote {
+					value = strconv.Quote(value)
+				} else if _, err := strconv.Atoi(value); err != nil {
+					return fmt.Errorf("field %s: %s requires an integer", f.name, key)
+				}
+				calls = append(calls, rule.method+"("+value+")")
+			default:
+				return fmt.Errorf("field %s: unknown validate option %q", f.name, opt)
+			}
+		}
+	}
+	var typed string
+	if slice {
+		kind, ok := elemKinds[cat]
+		if !ok && strings.Contains(strings.Join(calls, ""), "MustType(") {
+			kind, ok = elemKinds[catString], true
+		}
+		if !ok {
+			return fmt.Errorf("field %s: unsupported list element type", f.name)
+		}
+		if sep == "" {
+			sep = ","
+		}
+		g.reflect = true
+		typed = fmt.Sprintf("MustSeparator(%s, %s)", strconv.Quote(sep), kind)
+	} else {
+		switch cat {
+		case catSigned, catUnsigned:
+			typed = "MustInt()"
+		case catInt64:
+			typed = "MustInt64()"
+		case catFloat:
+			typed = "MustFloat64()"
+		case catBool:
+			typed = "MustBool()"
+		}
+	}
+	if typed != "" {
+		calls = append([]string{typed}, calls...)
+	}
+	g.printf("v.NewParam(%s%s)", strconv.Quote(f.param), def)
+	for _, c := range calls {
+		g.printf(".%s", c)
+	}
+	g.printf("\n")
+	return nil
+}
+
+//按逗号拆分validate标签，等号后以单引号包围的值可以包含逗号，如match='^\d{1,3}(,\d{3})*$'
+func splitOptions(opts string) ([]string, error) {
+	var result []string
+	var opt strings.Builder
+	quoted := false
+	for i := 0; i < len(opts); i++ {
+		c := opts[i]
+		switch {
+		case c == '\'' && (quoted || strings.HasSuffix(opt.String(), "=")):
+			quoted = !quoted
+		case c == ',' && !quoted:
+			result = append(result, opt.String())
+			opt.Reset()
+		default:
+			opt.WriteByte(c)
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote in %q", opts)
+	}
+	return append(result, opt.String()), nil
+}
+
+//未打标签的嵌套结构体字段，返回结构体定义及本包命名类型名
+func (g *generator) nestedStruct(expr ast.Expr) (*ast.StructType, string) {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	switch t := expr.(type) {
+	case *ast.StructType:
+		return t, ""
+	case *ast.Ident:
+		if g.category(t) == catStruct {
+			return g.types[t.Name].Type.(*ast.StructType), t.Name
+		}
+	}
+	return nil, ""
+}
+
+//生成BindFrom方法，嵌套的本包结构体类型也会生成
+func (g *generator) binder(name string, st *ast.StructType) error {
+	g.printf("//不使用反射的参数绑定，结果与ValuesToStruct一致\n")
+	g.printf("func (s *%s) BindFrom(v *%s) error {\n_, err := s.bindFrom(v)\nreturn err\n}\n\n", name, g.q("Validator"))
+	g.printf("func (s *%s) bindFrom(v *%s) (bool, error) {\nbound := false\n", name, g.q("Validator"))
+	if err := g.bindFields("s.", "", st, map[string]bool{name: true}); err != nil {
+		return fmt.Errorf("%s: %v", name, err)
+	}
+	g.printf("return bound, nil\n}\n\n")
+	return nil
+}
+
+//visiting为正在绑定的本包结构体类型，与ValuesToStruct一致，指向其中类型的指针字段跳过
+func (g *generator) bindFields(access, prefix string, st *ast.StructType, visiting map[string]bool) error {
+	for _, f := range fields(st) {
+		path := prefix + f.name
+		target := access + f.name
+		if f.param == "" {
+			if err := g.bindNested(target, path, f.field.Type, visiting); err != nil {
+				return err
+			}
+			continue
+		}
+		elem, ptr, slice := splitField(f.field.Type)
+		bind := "Bind"
+		switch {
+		case ptr && slice:
+			bind = "BindPtrSlice"
+		case slice:
+			bind = "BindSlice"
+		case ptr:
+			bind = "BindPtr"
+		}
+		switch elem.(type) {
+		case *ast.StarExpr, *ast.ArrayType, *ast.MapType:
+			return fmt.Errorf("field %s: unsupported field type", f.name)
+		}
+		g.printf("if ok, err := %s(v, %s, %s, &%s, %s); err != nil {\nreturn bound, err\n} else if ok {\nbound = true\n}\n",
+			g.q(bind), strconv.Quote(f.param), strconv.Quote(path), target, g.q(setFuncs[g.category(elem)]))
+	}
+	return nil
+}
+
+//未打标签的嵌套结构体展开绑定，指针字段在闭包中绑定，有参数绑定时才分配
+func (g *generator) bindNested(target, path string, expr ast.Expr, visiting map[string]bool) error {
+	nested, typeName := g.nestedStruct(expr)
+	if nested == nil {
+		return nil
+	}
+	_, ptr := expr.(*ast.StarExpr)
+	switch {
+	case typeName == "" && ptr:
+		return fmt.Errorf("field %s: unsupported pointer to anonymous struct", path)
+	case typeName == "":
+		return g.bindFields(target+".", path+".", nested, visiting)
+	case ptr && visiting[typeName]:
+		return nil
+	}
+	g.pending = append(g.pending, typeName)
+	visiting[typeName] = true
+	defer delete(visiting, typeName)
+	if !ptr {
+		return g.bindFields(target+".", path+".", nested, visiting)
+	}
+	g.printf("{\np := %s\nif p == nil {\np = new(%s)\n}\nok, err := func(s *%s) (bool, error) {\nbound := false\n", target, typeName, typeName)
+	if err := g.bindFields("s.", path+".", nested, visiting); err != nil {
+		return err
+	}
+	g.printf("return bound, nil\n}(p)\nif err != nil {\nreturn bound, err\n}\nif ok {\n%s = p\nbound = true\n}\n}\n", target)
+	return nil
+}
+
+//解析文件并生成代码，文件需包含所有被引用的本包类型
+func GenerateFiles(filenames []string, cfg Config) ([]byte, error) {
+	fset := token.NewFileSet()
+	files := make([]*ast.File, 0, len(filenames))
+	for _, name := range filenames {
+		f, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	return Generate(files, cfg)
+}
@@ -0,0 +1,256 @@
+package validator
+
+import (
+	"reflect"
+	"time"
+)
+
+//类型化的参数句柄，声明基于RuleSet，校验后通过Get读取声明类型的值
+//
+//	page := validator.Int(v, "page", 1).Min(1).Max(100)
+//	...
+//	n := page.Get(v)
+type Param[T any] struct {
+	name  string
+	rules RuleSet
+	conv  func(*Validator, interface{}) (T, bool)
+}
+
+func newParam[T any](v *Validator, name string, def []T, conv func(*Validator, interface{}) (T, bool)) Param[T] {
+	var rules RuleSet
+	if len(def) > 0 {
+		rules = v.NewParam(name, def[0])
+	} else {
+		rules = v.NewParam(name)
+	}
+	if conv == nil {
+		conv = assertValue[T]
+	}
+	return Param[T]{name: name, rules: rules, conv: conv}
+}
+
+//可比较大小的参数类型
+type Bounded interface {
+	int | int64 | float64 | time.Time
+}
+
+//数值或时间参数的句柄，在Param的基础上提供Min、Max等边界规则
+type BoundedParam[T Bounded] struct {
+	Param[T]
+}
+
+func assertValue[T any](_ *Validator, value interface{}) (T, bool) {
+	x, ok := value.(T)
+	return x, ok
+}
+
+func Int(v *Validator, name string, def ...int) BoundedParam[int] {
+	p := newParam(v, name, def, nil)
+	p.rules.MustInt()
+	return BoundedParam[int]{p}
+}
+
+func Int64(v *Validator, name string, def ...int64) BoundedParam[int64] {
+	p := newParam(v, name, def, nil)
+	p.rules.MustInt64()
+	return BoundedParam[int64]{p}
+}
+
+func Float64(v *Validator, name string, def ...float64) BoundedParam[float64] {
+	p := newParam(v, name, def, nil)
+	p.rules.MustFloat64()
+	return BoundedParam[float64]{p}
+}
+
+func Bool(v *Validator, name string, def ...bool) Param[bool] {
+	p := newParam(v, name, def, nil)
+	p.rules.MustBool()
+	return p
+}
+
+func String(v *Validator, name string, def ...string) Param[string] {
+	return newParam(v, name, def, nil)
+}
+
+//已注册的自定义类型参数，T为解析函数返回值的类型
+func Custom[T any](v *Validator, name, typeName string) Param[T] {
+	p := newParam[T](v, name, nil, nil)
+	p.rules.MustType(typeName)
+	return p
+}
+
+//时间参数，Get返回按layout解析后的时间
+func Time(v *Validator, name, layout string) BoundedParam[time.Time] {
+	p := newParam(v, name, nil, func(result *Validator, value interface{}) (time.Time, bool) {
+		s, ok := value.(string)
+		if !ok {
+			return time.Time{}, false
+		}
+		t, err := time.Parse(result.timeLayoutMap[name], s)
+		return t, err == nil
+	})
+	p.rules.MustTimeLayout(layout)
+	return BoundedParam[time.Time]{p}
+}
+
+func Ints(v *Validator, name, sep string) Param[[]int] {
+	return sliceParam[int](v, name, sep, reflect.Int)
+}
+
+func Int64s(v *Validator, name, sep string) Param[[]int64] {
+	return sliceParam[int64](v, name, sep, reflect.Int64)
+}
+
+func Float64s(v *Validator, name, sep string) Param[[]float64] {
+	return sliceParam[float64](v, name, sep, reflect.Float64)
+}
+
+func Strings(v *Validator, name, sep string) Param[[]string] {
+	return sliceParam[string](v, name, sep, reflect.String)
+}
+
+func sliceParam[E any](v *Validator, name, sep string, kind reflect.Kind) Param[[]E] {
+	p := newParam(v, name, nil, func(_ *Validator, value interface{}) ([]E, bool) {
+		values, ok := value.([]interface{})
+		if !ok {
+			return nil, false
+		}
+		s := make([]E, 0, len(values))
+		for _, elem := range values {
+			e, ok := elem.(E)
+			if !ok {
+				return nil, false
+			}
+			s = append(s, e)
+		}
+		return s, true
+	})
+	p.rules.MustSeparator(sep, kind)
+	return p
+}
+
+func (p Param[T]) Name() string {
+	return p.name
+}
+
+//底层的RuleSet，用于链式声明Param没有封装的规则
+func (p Param[T]) Rules() RuleSet {
+	return p.rules
+}
+
+//校验后的值，未传值且没有默认值时为零值
+func (p Param[T]) Get(result *Validator) T {
+	value, _ := p.Lookup(result)
+	return value
+}
+
+//校验后的值及是否有值
+func (p Param[T]) Lookup(result *Validator) (T, bool) {
+	value, ok := result.Value(p.name)
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return p.conv(result, value)
+}
+
+func (p Param[T]) Require() Param[T] {
+	p.rules.Require(true)
+	return p
+}
+
+func (p Param[T]) Description(description string) Param[T] {
+	p.rules.Description(description)
+	return p
+}
+
+func (p Param[T]) Label(label string) Param[T] {
+	p.rules.Label(label)
+	return p
+}
+
+//大于等于bound
+func (p BoundedParam[T]) Min(bound T) BoundedParam[T] {
+	p.rules.MustMinValue(bound)
+	return p
+}
+
+//小于等于bound
+func (p BoundedParam[T]) Max(bound T) BoundedParam[T] {
+	p.rules.MustMaxValue(bound)
+	return p
+}
+
+//大于bound
+func (p BoundedParam[T]) GreaterThan(bound T) BoundedParam[T] {
+	p.rules.MustGreaterThanValue(bound)
+	return p
+}
+
+//小于bound
+func (p BoundedParam[T]) LessThan(bound T) BoundedParam[T] {
+	p.rules.MustLessThanValue(bound)
+	return p
+}
+
+func (p Param[T]) Length(length int) Param[T] {
+	p.rules.MustLength(length)
+	return p
+}
+
+func (p Param[T]) LengthRange(min, max int) Param[T] {
+	p.rules.MustLengthRange(min, max)
+	return p
+}
+
+func (p Param[T]) Match(pattern string) Param[T] {
+	p.rules.MustMatch(pattern)
+	return p
+}
+
+//可选值
+func (p Param[T]) Values(values ...T) Param[T] {
+	p.rules.MustValues(toInterfaces(values))
+	return p
+}
+
+//不允许的值
+func (p Param[T]) NotValues(values ...T) Param[T] {
+	p.rules.MustNotValues(toInterfaces(values))
+	return p
+}
+
+func toInterfaces[T any](values []T) []interface{} {
+	out := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		out = append(out, value)
+	}
+	return out
+}
+
+//以下方法与Param相同，返回BoundedParam以便继续声明边界规则
+
+func (p BoundedParam[T]) Require() BoundedParam[T] {
+	p.Param = p.Param.Require()
+	return p
+}
+
+func (p BoundedParam[T]) Description(description string) BoundedParam[T] {
+	p.Param = p.Param.Description(description)
+	return p
+}
+
+func (p BoundedParam[T]) Label(label string) BoundedParam[T] {
+	p.Param = p.Param.Label(label)
+	return p
+}
+
+func (p BoundedParam[T]) Values(values ...T) BoundedParam[T] {
+	p.Param = p.Param.Values(values...)
+	return p
+}
+
+func (p BoundedParam[T]) NotValues(values ...T) BoundedParam[T] {
+	p.Param = p.Param.NotValues(values...)
+	return p
+}
@@ -0,0 +1,140 @@
+package validator
+
+import (
+	"bufio"
+	"net/url"
+	"os"
+	"strings"
+	"unicode"
+)
+
+//密码策略中的检查项，校验失败时作为ParamsError的Args
+const (
+	PasswordMinLength     = "min_length"
+	PasswordRequireLower  = "require_lower"
+	PasswordRequireUpper  = "require_upper"
+	PasswordRequireDigit  = "require_digit"
+	PasswordRequireSymbol = "require_symbol"
+	PasswordMaxRepeat     = "max_repeat"
+	PasswordForbiddenSeq  = "forbidden_sequence"
+	PasswordBlocklist     = "blocklist"
+)
+
+//密码强度策略，零值的检查项不生效
+type PasswordPolicy struct {
+	MinLength     int
+	RequireLower  bool
+	RequireUpper  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	//同一字符最多连续出现的次数
+	MaxRepeat int
+	//不允许包含的序列，忽略大小写，如"123456"、"qwerty"
+	ForbiddenSequences []string
+	blocklist          map[string]struct{}
+}
+
+//从文件加载常见密码黑名单，每行一个，忽略大小写
+func (p *PasswordPolicy) LoadBlocklist(filename string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if p.blocklist == nil {
+		p.blocklist = make(map[string]struct{})
+	}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p.blocklist[strings.ToLower(line)] = struct{}{}
+	}
+	return scanner.Err()
+}
+
+//返回未满足的检查项
+func (p *PasswordPolicy) Check(password string) []string {
+	var failed []string
+	var lower, upper, digit, symbol bool
+	var last rune
+	repeat, maxRepeat, length := 0, 0, 0
+	for _, c := range password {
+		length++
+		switch {
+		case unicode.IsLower(c):
+			lower = true
+		case unicode.IsUpper(c):
+			upper = true
+		case unicode.IsDigit(c):
+			digit = true
+		case unicode.IsPunct(c) || unicode.IsSymbol(c):
+			symbol = true
+		}
+		if c == last {
+			repeat++
+		} else {
+			repeat = 1
+			last = c
+		}
+		if repeat > maxRepeat {
+			maxRepeat = repeat
+		}
+	}
+	if p.MinLength > 0 && length < p.MinLength {
+		failed = append(failed, PasswordMinLength)
+	}
+	if p.RequireLower && !lower {
+		failed = append(failed, PasswordRequireLower)
+	}
+	if p.RequireUpper && !upper {
+		failed = append(failed, PasswordRequireUpper)
+	}
+	if p.RequireDigit && !digit {
+		failed = append(failed, PasswordRequireDigit)
+	}
+	if p.RequireSymbol && !symbol {
+		failed = append(failed, PasswordRequireSymbol)
+	}
+	if p.MaxRepeat > 0 && maxRepeat > p.MaxRepeat {
+		failed = append(failed, PasswordMaxRepeat)
+	}
+	lowerPassword := strings.ToLower(password)
+	for _, seq := range p.ForbiddenSequences {
+		if seq != "" && strings.Contains(lowerPassword, strings.ToLower(seq)) {
+			failed = append(failed, PasswordForbiddenSeq)
+			break
+		}
+	}
+	if _, ok := p.blocklist[lowerPassword]; ok {
+		failed = append(failed, PasswordBlocklist)
+	}
+	return failed
+}
+
+//错误中不包含参数值
+func mustPasswordPolicy(k string, v interface{}, params url.Values, cus bool, args ...interface{}) error {
+	vString, ok := v.(string)
+	if !ok {
+		return nil
+	}
+	failed := args[0].(*PasswordPolicy).Check(vString)
+	if len(failed) == 0 {
+		return nil
+	}
+	pErr := NewParamsError(k, nil)
+	for _, item := range failed {
+		pErr.Args = append(pErr.Args, item)
+	}
+	return pErr.ErrMustPasswordPolicy(cus)
+}
+
+//参数会被标记为Sensitive
+func (r *ruleSet) MustPasswordPolicy(policy *PasswordPolicy) RuleSet {
+	if r.paramName != "" {
+		r.Sensitive()
+	}
+	return r.appendRule("MustPasswordPolicy", mustPasswordPolicy, "密码策略", policy)
+}
@@ -0,0 +1,105 @@
+package validator
+
+//参数的出现状态
+type Presence int
+
+const (
+	//未传
+	PresenceAbsent Presence = iota
+	//传了空值
+	PresenceEmpty
+	//传了NullToken
+	PresenceNull
+	//传了非空值
+	PresenceValue
+)
+
+func (p Presence) String() string {
+	switch p {
+	case PresenceEmpty:
+		return "empty"
+	case PresenceNull:
+		return "null"
+	case PresenceValue:
+		return "value"
+	}
+	return "absent"
+}
+
+//设置表示显式null的参数值，如"null"，为空时不识别null
+func (v *Validator) SetNullToken(token string) *Validator {
+	v.NullToken = token
+	return v
+}
+
+func (v *Validator) isEmpty(value string) bool {
+	return value == "" || v.NullToken != "" && value == v.NullToken
+}
+
+//记录参数的出现状态，返回是否跳过规则，null和未声明CheckEmpty的空值不执行规则
+func (v *Validator) presenceCheck(key, value string) (bool, error) {
+	switch {
+	case v.NullToken != "" && value == v.NullToken:
+		v.presenceMap[key] = PresenceNull
+	case value == "":
+		v.presenceMap[key] = PresenceEmpty
+	default:
+		v.presenceMap[key] = PresenceValue
+		return false, nil
+	}
+	p, ok := v.ApiParams[key]
+	if !ok {
+		return true, nil
+	}
+	if p.NotEmptyIfPresent && !p.AllowEmpty {
+		return true, NewParamsError(key, value).ErrMustNotEmpty(v.CustomError)
+	}
+	return !v.ruleChecked(key), nil
+}
+
+//最近一次校验中参数是否执行了规则
+func (v *Validator) ruleChecked(name string) bool {
+	switch v.presenceMap[name] {
+	case PresenceValue:
+		return true
+	case PresenceEmpty:
+		p, ok := v.ApiParams[name]
+		return ok && p.CheckEmpty
+	}
+	return false
+}
+
+//最近一次校验中参数的出现状态
+func (v *Validator) Presence(paramName string) Presence {
+	return v.presenceMap[paramName]
+}
+
+//最近一次校验中所有已声明参数的出现状态
+func (v *Validator) Presences() map[string]Presence {
+	presences := make(map[string]Presence, len(v.ApiParams))
+	for name := range v.ApiParams {
+		presences[name] = v.presenceMap[name]
+	}
+	return presences
+}
+
+//必须参数允许传空值或null
+func (r *ruleSet) AllowEmpty() RuleSet {
+	r.valid.own()
+	r.valid.ApiParams[r.paramName].AllowEmpty = true
+	return r
+}
+
+//传了空值时也执行类型转换和规则
+func (r *ruleSet) CheckEmpty() RuleSet {
+	r.valid.own()
+	r.valid.ApiParams[r.paramName].CheckEmpty = true
+	return r
+}
+
+//非必须参数，传了就不能为空值或null
+func (r *ruleSet) NotEmptyIfPresent() RuleSet {
+	r.valid.own()
+	r.valid.ApiParams[r.paramName].NotEmptyIfPresent = true
+	return r
+}
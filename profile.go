@@ -0,0 +1,165 @@
+package validator
+
+import "sort"
+
+//同一个Validator的不同校验场景，如create和update，声明保存在所属的Validator中
+type Profile struct {
+	Name  string
+	valid *Validator
+}
+
+type profileOp struct {
+	kind  string
+	names []string
+}
+
+const (
+	profileOptional = "optional"
+	profileRequire  = "require"
+	profileForbid   = "forbid"
+)
+
+//获取或创建profile，"*"表示所有参数
+func (v *Validator) Profile(name string) *Profile {
+	v.own()
+	if v.profiles == nil {
+		v.profiles = make(map[string][]profileOp)
+	}
+	if _, ok := v.profiles[name]; !ok {
+		v.profiles[name] = nil
+	}
+	return &Profile{Name: name, valid: v}
+}
+
+func (p *Profile) Optional(names ...string) *Profile {
+	return p.addOp(profileOptional, names)
+}
+
+func (p *Profile) Require(names ...string) *Profile {
+	return p.addOp(profileRequire, names)
+}
+
+//禁止传入的参数，如update时不可修改的字段
+func (p *Profile) Forbid(names ...string) *Profile {
+	return p.addOp(profileForbid, names)
+}
+
+//派生的Validator可能共享同一个切片，追加前先复制
+func (p *Profile) addOp(kind string, names []string) *Profile {
+	p.valid.own()
+	ops := p.valid.profiles[p.Name]
+	p.valid.profiles[p.Name] = append(ops[:len(ops):len(ops)], profileOp{kind: kind, names: append([]string(nil), names...)})
+	return p
+}
+
+//根据profile生成派生的Validator，原Validator不受影响
+func (p *Profile) Validator() *Validator {
+	return p.valid.profileValidator(p.Name)
+}
+
+func (v *Validator) profileValidator(profile string) *Validator {
+	pv := v.cloneWithoutProfiles()
+	for _, op := range v.profiles[profile] {
+		names := op.names
+		if len(names) == 1 && names[0] == "*" {
+			names = nil
+			for name := range pv.ApiParams {
+				names = append(names, name)
+			}
+		}
+		for _, name := range names {
+			switch op.kind {
+			case profileOptional:
+				pv.setRequire(name, false)
+			case profileRequire:
+				pv.setRequire(name, true)
+			case profileForbid:
+				pv.setRequire(name, false)
+				pv.forbidParams[name] = true
+			}
+		}
+	}
+	return pv
+}
+
+func (v *Validator) cloneWithoutProfiles() *Validator {
+	d := v.Clone()
+	d.profiles = nil
+	return d
+}
+
+func (v *Validator) setRequire(name string, require bool) {
+	if p, ok := v.ApiParams[name]; ok {
+		p.Require = require
+	}
+	isUrlParam := containsString(v.requireUrlParams, name)
+	v.requireParams = removeString(v.requireParams, name)
+	v.requireUrlParams = removeString(v.requireUrlParams, name)
+	if !require {
+		return
+	}
+	if isUrlParam {
+		v.requireUrlParams = append(v.requireUrlParams, name)
+	} else {
+		v.requireParams = append(v.requireParams, name)
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func removeString(list []string, s string) []string {
+	result := list[:0]
+	for _, item := range list {
+		if item != s {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+//文档中profile的参数要求
+type ProfileDoc struct {
+	Name   string
+	Params []ProfileParamDoc
+}
+
+type ProfileParamDoc struct {
+	Name   string
+	Status string
+}
+
+func (v *Validator) ProfileDocs() []ProfileDoc {
+	var names []string
+	for name := range v.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var docs []ProfileDoc
+	for _, name := range names {
+		pv := v.profileValidator(name)
+		doc := ProfileDoc{Name: name}
+		var params []string
+		for param := range pv.ApiParams {
+			params = append(params, param)
+		}
+		sort.Strings(params)
+		for _, param := range params {
+			status := "可选"
+			if pv.forbidParams[param] {
+				status = "禁止"
+			} else if pv.ApiParams[param].Require {
+				status = "必须"
+			}
+			doc.Params = append(doc.Params, ProfileParamDoc{Name: param, Status: status})
+		}
+		docs = append(docs, doc)
+	}
+	return docs
+}
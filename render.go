@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"html/template"
 	"io"
+	"net/http"
 	"os"
 	"strings"
+	"sync"
+	texttemplate "text/template"
 
 	"github.com/go-wyvern/leego"
 )
@@ -26,6 +29,7 @@ type Api struct {
 	FailFormat    []byte
 	StdFormat     string
 	CodeTag       string
+	ErrorRenderer string
 	Validator     *Validator
 }
 
@@ -85,7 +89,34 @@ func (c *Api) SetFailStdOut(s interface{}) {
 	}
 }
 
+//设置Api使用的错误渲染器，文档中的错误返回示例和WriteError使用同一渲染器
+func (c *Api) SetFailRenderer(name string) {
+	c.ErrorRenderer = name
+	c.FailStdOut = nil
+	c.FailFormat = nil
+}
+
+//文档中的错误返回示例，未通过SetFailStdOut设置时在生成文档时用错误渲染器渲染
+func (c *Api) FailDoc() []byte {
+	if c.FailStdOut != nil || len(c.FailFormat) > 0 {
+		return c.FailFormat
+	}
+	_, _, body := GetErrorRenderer(c.ErrorRenderer).Render(sampleError(c.Validator))
+	return indentJson(body)
+}
+
+//使用Api的错误渲染器将错误写入http响应，未设置时使用DefaultErrorRenderer
+func (c *Api) WriteError(w http.ResponseWriter, err error) error {
+	return writeError(w, GetErrorRenderer(c.ErrorRenderer), err)
+}
+
+//注册Api，Validator必须通过Compile检查
 func (c *Module) Use(a Api) *Module {
+	if a.Validator != nil {
+		if err := a.Validator.Compile(); err != nil {
+			panic(fmt.Sprintf("%s %s: %s", a.Method, a.Path, err.Error()))
+		}
+	}
 	c.Apis = append(c.Apis, a)
 	AppApis = append(AppApis, a)
 	return c
@@ -100,10 +131,24 @@ func (c *Project) RenderMarkdown(filename string) error {
 	var err error
 	f, err := os.Create(filename)
 	if err != nil {
-		fmt.Println(err.Error())
 		return err
 	}
-	err = tmpl(f, MarkdownTemplate, c)
+	defer f.Close()
+	err = textTmpl(f, MarkdownTemplate, c)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *Project) RenderHTML(filename string) error {
+	var err error
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	err = tmpl(f, HtmlTemplate, c)
 	if err != nil {
 		return err
 	}
@@ -118,10 +163,16 @@ var MarkdownTemplate = `{{with .}}# {{.ProjectName}}
 
 请求参数:
 
-| 名称 | 类型 | 说明           | 是否必须  |
-| -----|:-----:|:---------:|:-----:|
-{{range $name, $params :=.Validator.ApiParams}}|**{{$name}}**|{{$params.Type}}|{{$params.Description}}|{{$params.Require}}|
-{{end}}
+| 名称 | 显示名 | 类型 | 说明           | 是否必须  | 默认值 | 预处理 | 规则 | 来源 |
+| -----|:-----:|:-----:|:---------:|:-----:|:-----:|:-----:|:-----:|:-----:|
+{{range $name, $params :=.Validator.ApiParams}}|**{{$name}}**|{{$params.Label}}|{{$params.Type}}|{{$params.Description}}|{{$params.Require}}|{{$params.DefaultDoc|cell}}|{{$params.TransformDoc|cell}}|{{$params.RuleDoc|cell}}|{{$params.Source}}|
+{{end}}{{range .Validator.ProfileDocs}}
+场景 {{.Name}}:
+
+| 名称 | 要求 |
+| -----|:-----:|
+{{range .Params}}|**{{.Name}}**|{{.Status}}|
+{{end}}{{end}}
 请求正确返回:
 
 {{.CodeTag}}
@@ -131,12 +182,41 @@ var MarkdownTemplate = `{{with .}}# {{.ProjectName}}
 请求错误返回:
 
 {{.CodeTag}}
-{{.FailFormat | printf "%s"|unescaped}}
+{{.FailDoc | printf "%s"|unescaped}}
 {{.CodeTag}}
 
 {{end}}{{end}}{{end}}
 `
 
+var HtmlTemplate = `{{with .}}<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.ProjectName}}</title></head>
+<body>
+<h1>{{.ProjectName}}</h1>
+{{range .Modules}}
+<h2>{{.ModuleName}}</h2>
+{{range .Apis}}
+<h3>{{.Method}} {{.Path}} {{.Description}}</h3>
+<p>请求参数:</p>
+<table>
+<tr><th>名称</th><th>显示名</th><th>类型</th><th>说明</th><th>是否必须</th><th>默认值</th><th>预处理</th><th>规则</th><th>来源</th></tr>
+{{range $name, $params :=.Validator.ApiParams}}<tr><td><b>{{$name}}</b></td><td>{{$params.Label}}</td><td>{{$params.Type}}</td><td>{{$params.Description}}</td><td>{{$params.Require}}</td><td>{{$params.DefaultDoc}}</td><td>{{$params.TransformDoc}}</td><td>{{$params.RuleDoc}}</td><td>{{$params.Source}}</td></tr>
+{{end}}</table>
+{{range .Validator.ProfileDocs}}<p>场景 {{.Name}}:</p>
+<table>
+<tr><th>名称</th><th>要求</th></tr>
+{{range .Params}}<tr><td><b>{{.Name}}</b></td><td>{{.Status}}</td></tr>
+{{end}}</table>
+{{end}}<p>请求正确返回:</p>
+<pre>{{.SuccessFormat|printf "%s"}}</pre>
+<p>请求错误返回:</p>
+<pre>{{.FailDoc|printf "%s"}}</pre>
+{{end}}{{end}}
+</body>
+</html>
+{{end}}`
+
+//html文档渲染，会对内容进行html转义
 func tmpl(w io.Writer, text string, data interface{}) error {
 	t := template.New("top")
 	t.Funcs(template.FuncMap{"trim": func(s template.HTML) template.HTML {
@@ -146,9 +226,46 @@ func tmpl(w io.Writer, text string, data interface{}) error {
 	t.Funcs(template.FuncMap{"unescaped": func(x string) interface{} {
 		return template.HTML(x)
 	}})
-	template.Must(t.Parse(text))
+	if _, err := t.Parse(text); err != nil {
+		return err
+	}
 	if err := t.Execute(w, data); err != nil {
 		return err
 	}
 	return nil
 }
+
+//已解析的内置错误信息模板缓存，key为模板原文，只缓存内置模板，数量固定
+var textTemplates sync.Map
+
+var textFuncs = texttemplate.FuncMap{
+	"trim":      strings.TrimSpace,
+	"unescaped": func(x string) string { return x },
+	"cell":      func(x string) string { return strings.Replace(x, "|", "\\|", -1) },
+}
+
+func parseTextTmpl(text string) (*texttemplate.Template, error) {
+	return texttemplate.New("top").Funcs(textFuncs).Parse(text)
+}
+
+//内置错误信息模板，解析一次后缓存
+func builtinTmpl(text string) (*texttemplate.Template, error) {
+	if t, ok := textTemplates.Load(text); ok {
+		return t.(*texttemplate.Template), nil
+	}
+	t, err := parseTextTmpl(text)
+	if err != nil {
+		return nil, err
+	}
+	textTemplates.Store(text, t)
+	return t, nil
+}
+
+//markdown文档渲染，不做html转义
+func textTmpl(w io.Writer, text string, data interface{}) error {
+	t, err := parseTextTmpl(text)
+	if err != nil {
+		return err
+	}
+	return t.Execute(w, data)
+}
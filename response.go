@@ -0,0 +1,210 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+const (
+	ProblemRendererName = "problem"
+	SimpleRendererName  = "simple"
+
+	ProblemContentType = "application/problem+json"
+	JsonContentType    = "application/json; charset=utf-8"
+)
+
+//将参数校验错误渲染为http响应
+type ErrorRenderer interface {
+	Render(err error) (status int, contentType string, body []byte)
+}
+
+type ErrorRendererFunc func(err error) (int, string, []byte)
+
+func (f ErrorRendererFunc) Render(err error) (int, string, []byte) {
+	return f(err)
+}
+
+var DefaultErrorRenderer = ProblemRendererName
+
+//非参数错误的响应信息，不暴露错误的具体内容
+const internalErrorText = "服务器内部错误"
+
+var (
+	renderersMu    sync.RWMutex
+	errorRenderers = map[string]ErrorRenderer{
+		ProblemRendererName: &ProblemRenderer{},
+		SimpleRendererName:  &SimpleRenderer{},
+	}
+)
+
+//注册自定义的错误渲染器，同名会覆盖
+func RegisterErrorRenderer(name string, r ErrorRenderer) {
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+	errorRenderers[name] = r
+}
+
+//获取错误渲染器，找不到时使用DefaultErrorRenderer
+func GetErrorRenderer(name string) ErrorRenderer {
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+	if r, ok := errorRenderers[name]; ok {
+		return r
+	}
+	return errorRenderers[DefaultErrorRenderer]
+}
+
+//使用默认渲染器渲染错误
+func RenderError(err error) (int, string, []byte) {
+	return GetErrorRenderer(DefaultErrorRenderer).Render(err)
+}
+
+//使用默认渲染器将错误写入http响应
+func WriteError(w http.ResponseWriter, err error) error {
+	return writeError(w, GetErrorRenderer(DefaultErrorRenderer), err)
+}
+
+func writeError(w http.ResponseWriter, r ErrorRenderer, err error) error {
+	status, contentType, body := r.Render(err)
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	_, werr := w.Write(body)
+	return werr
+}
+
+//RFC 7807 application/problem+json
+type ProblemRenderer struct {
+	Type   string
+	Title  string
+	Status int
+}
+
+type invalidParam struct {
+	Name   string        `json:"name"`
+	Reason string        `json:"reason"`
+	Code   ErrorCode     `json:"code,omitempty"`
+	Args   []interface{} `json:"args,omitempty"`
+}
+
+type problem struct {
+	Type          string         `json:"type"`
+	Title         string         `json:"title"`
+	Status        int            `json:"status"`
+	Detail        string         `json:"detail,omitempty"`
+	InvalidParams []invalidParam `json:"invalid-params,omitempty"`
+}
+
+//非参数错误，如ValidateContext返回的context.Canceled，渲染为500，detail为通用信息且不包含invalid-params
+func (r *ProblemRenderer) Render(err error) (int, string, []byte) {
+	p := problem{
+		Type:   r.Type,
+		Title:  r.Title,
+		Status: r.Status,
+		Detail: err.Error(),
+	}
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+	if !isParamsError(err) {
+		p.Title = "服务器错误"
+		p.Status = http.StatusInternalServerError
+		p.Detail = internalErrorText
+		body, _ := json.Marshal(p)
+		return p.Status, ProblemContentType, body
+	}
+	if p.Title == "" {
+		p.Title = "参数错误"
+	}
+	if p.Status == 0 {
+		p.Status = http.StatusBadRequest
+	}
+	for _, pErr := range AsParamsErrors(err) {
+		p.InvalidParams = append(p.InvalidParams, invalidParam{
+			Name:   pErr.Key,
+			Reason: pErr.Text,
+			Code:   pErr.Code,
+			Args:   pErr.Args,
+		})
+	}
+	body, _ := json.Marshal(p)
+	return p.Status, ProblemContentType, body
+}
+
+//{code,msg,fields}格式
+type SimpleRenderer struct {
+	Status int
+}
+
+type simpleBody struct {
+	Code   ErrorCode    `json:"code"`
+	Msg    string       `json:"msg"`
+	Fields ParamsErrors `json:"fields"`
+}
+
+//非参数错误渲染为500，fields为空
+func (r *SimpleRenderer) Render(err error) (int, string, []byte) {
+	s := simpleBody{
+		Msg:    internalErrorText,
+		Fields: ParamsErrors{},
+	}
+	status := http.StatusInternalServerError
+	if isParamsError(err) {
+		s.Msg = err.Error()
+		s.Fields = AsParamsErrors(err)
+		if len(s.Fields) > 0 {
+			s.Code = s.Fields[0].Code
+		}
+		status = r.Status
+		if status == 0 {
+			status = http.StatusBadRequest
+		}
+	}
+	body, _ := json.Marshal(s)
+	return status, JsonContentType, body
+}
+
+//校验返回的参数错误，其他错误由渲染器作为服务端错误处理
+func isParamsError(err error) bool {
+	switch err.(type) {
+	case *ParamsError, ParamsErrors:
+		return true
+	}
+	return false
+}
+
+//根据validator生成一个示例错误，用于文档
+func sampleError(v *Validator) error {
+	if v == nil {
+		return NewParamsError("foo", nil).ErrUnknownParam(false)
+	}
+	return v.labelError(sampleParamsError(v))
+}
+
+func sampleParamsError(v *Validator) error {
+	if len(v.requireParams) > 0 {
+		return NewParamsError(v.requireParams[0], nil).ErrRequireParam(v.CustomError)
+	}
+	if len(v.requireUrlParams) > 0 {
+		return NewParamsError(v.requireUrlParams[0], nil).ErrRequireParam(v.CustomError)
+	}
+	var names []string
+	for name := range v.ApiParams {
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return NewParamsError("foo", nil).ErrUnknownParam(v.CustomError)
+	}
+	sort.Strings(names)
+	return NewParamsError(names[0], nil).ErrRequireNotNull(v.CustomError)
+}
+
+func indentJson(body []byte) []byte {
+	var b bytes.Buffer
+	if err := json.Indent(&b, body, "", "  "); err != nil {
+		return body
+	}
+	return b.Bytes()
+}
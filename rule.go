@@ -0,0 +1,153 @@
+package validator
+
+import (
+	"context"
+	"net/url"
+	"sort"
+)
+
+//上下文规则中读取参数，除类型化的参数值外还可以读取当前参数名和原始请求参数
+type Fields interface {
+	ValueGetter
+	Param() string
+	Raw() url.Values
+}
+
+//带上下文的校验规则，ctx为ValidateContext传入的上下文，包含超时及请求范围的数据
+type Rule interface {
+	Validate(ctx context.Context, value interface{}, fields Fields) Outcome
+}
+
+type RuleFunc func(ctx context.Context, value interface{}, fields Fields) Outcome
+
+func (f RuleFunc) Validate(ctx context.Context, value interface{}, fields Fields) Outcome {
+	return f(ctx, value, fields)
+}
+
+//规则的执行结果
+type Outcome struct {
+	//校验失败时的错误码，为空时使用must_func
+	Code ErrorCode
+	//错误信息，原样返回，不会被当作模板解析，为空时使用must_func的默认信息
+	Message string
+	Args    []interface{}
+	//规则执行出错，例如查询超时，原样返回
+	Err    error
+	failed bool
+}
+
+//校验通过
+func Pass() Outcome {
+	return Outcome{}
+}
+
+//校验失败
+func Fail(code ErrorCode, message string, args ...interface{}) Outcome {
+	return Outcome{Code: code, Message: message, Args: args, failed: true}
+}
+
+//规则无法完成校验，err会原样返回，*ParamsError同样原样返回
+func Abort(err error) Outcome {
+	return Outcome{Err: err, failed: err != nil}
+}
+
+func (o Outcome) Failed() bool {
+	return o.failed
+}
+
+type ruleFields struct {
+	*Validator
+	name string
+	raw  url.Values
+}
+
+func (f *ruleFields) Param() string {
+	return f.name
+}
+
+func (f *ruleFields) Raw() url.Values {
+	return f.raw
+}
+
+//校验参数的上下文规则
+func (r *ruleSet) MustRule(rl Rule) RuleSet {
+	if r.setError != nil {
+		return r
+	}
+	if r.paramName == "" {
+		panic("unknown param name when set MustRule")
+	}
+	r.addRule(&rule{name: "MustRule", ctxRule: rl})
+	return r
+}
+
+//将ValidationFunc适配为Rule，已有的MustFunc规则可以直接迁移
+func AdaptFunc(f ValidationFunc, args ...interface{}) Rule {
+	f = withDefaultCode(f, CodeMustFunc)
+	return RuleFunc(func(ctx context.Context, value interface{}, fields Fields) Outcome {
+		cus := false
+		if rf, ok := fields.(*ruleFields); ok {
+			cus = rf.CustomError
+		}
+		return Abort(f(fields.Param(), value, fields.Raw(), cus, args...))
+	})
+}
+
+//上下文规则在其他规则和默认值之后执行，可以读取所有参数的值，只对执行了规则的参数执行
+func (v *Validator) runContextRules(ctx context.Context, params url.Values) error {
+	var names []string
+	for name, rules := range v.ruleMap {
+		if !v.ruleChecked(name) {
+			continue
+		}
+		for _, rl := range rules {
+			if rl.ctxRule != nil {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := v.valueMap[name]
+		fields := &ruleFields{Validator: v, name: name, raw: params}
+		for _, rl := range v.ruleMap[name] {
+			if rl.ctxRule == nil {
+				continue
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := v.outcomeError(name, value, rl.ctxRule.Validate(ctx, value, fields)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+//将规则结果转换为错误
+func (v *Validator) outcomeError(name string, value interface{}, o Outcome) error {
+	if !o.failed {
+		return nil
+	}
+	if o.Err != nil {
+		return o.Err
+	}
+	code := o.Code
+	if code == "" {
+		code = CodeMustFunc
+	}
+	pErr := NewParamsError(name, value)
+	pErr.Args = o.Args
+	switch {
+	case o.Message == "":
+		pErr.ErrMustFunc(v.CustomError)
+	case v.CustomError:
+		pErr.Text = "{{." + string(code) + "}}"
+	default:
+		pErr.Text = o.Message
+	}
+	pErr.Code = code
+	return pErr
+}
@@ -0,0 +1,159 @@
+package validator
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"sort"
+)
+
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+//与JSON Schema中format名称不同的格式
+var schemaFormats = map[string]string{
+	"url": "uri",
+}
+
+//导出参数声明的JSON Schema
+func (v *Validator) JSONSchema() ([]byte, error) {
+	return json.MarshalIndent(v.Schema(), "", "  ")
+}
+
+//参数声明对应的JSON Schema对象，列表参数为array，自定义类型为带format的string
+func (v *Validator) Schema() map[string]interface{} {
+	properties := make(map[string]interface{}, len(v.ApiParams))
+	for name := range v.ApiParams {
+		properties[name] = v.paramSchema(name)
+	}
+	schema := map[string]interface{}{
+		"$schema":              jsonSchemaDraft,
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if v.Name != "" {
+		schema["title"] = v.Name
+	}
+	var required []string
+	for _, name := range v.requireParams {
+		if !containsString(required, name) {
+			required = append(required, name)
+		}
+	}
+	if len(required) > 0 {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+	return schema
+}
+
+func (v *Validator) paramSchema(name string) map[string]interface{} {
+	p := v.ApiParams[name]
+	kind, ok := v.typeMap[name]
+	if !ok {
+		kind = reflect.String
+	}
+	var schema, item map[string]interface{}
+	if kind == reflect.Slice {
+		item = v.kindSchema(name, v.elemTypeMap[name])
+		schema = map[string]interface{}{"type": "array", "items": item}
+	} else {
+		schema = v.kindSchema(name, kind)
+		item = schema
+	}
+	if p.Label != "" {
+		schema["title"] = p.Label
+	}
+	if p.Description != "" {
+		schema["description"] = p.Description
+	}
+	if value, ok := v.defaultValueMap[name]; ok {
+		schema["default"] = v.displayValue(name, value)
+	}
+	if p.DynamicDefault {
+		schema["x-dynamic-default"] = true
+	}
+	for _, rl := range v.ruleMap[name] {
+		ruleSchema(item, rl, func(value interface{}) interface{} { return v.displayValue(name, value) })
+	}
+	return schema
+}
+
+//参数值类型的schema，列表参数为元素类型
+func (v *Validator) kindSchema(name string, kind reflect.Kind) map[string]interface{} {
+	switch kind {
+	case reflect.Int, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Interface:
+		if t, ok := v.customTypeMap[name]; ok {
+			return map[string]interface{}{"type": "string", "format": t.name}
+		}
+	}
+	schema := map[string]interface{}{"type": "string"}
+	if f, ok := v.formatMap[name]; ok {
+		if format, ok := schemaFormats[f.name]; ok {
+			schema["format"] = format
+		} else {
+			schema["format"] = f.name
+		}
+	}
+	return schema
+}
+
+//schema及文档中展示的值，自定义类型使用其格式化函数
+func (v *Validator) displayValue(name string, value interface{}) interface{} {
+	switch vt := value.(type) {
+	case []interface{}:
+		values := make([]interface{}, 0, len(vt))
+		for _, elem := range vt {
+			values = append(values, v.displayValue(name, elem))
+		}
+		return values
+	}
+	if t, ok := v.customTypeMap[name]; ok {
+		if text, err := t.format(value); err == nil {
+			return text
+		}
+	}
+	return value
+}
+
+//规则对应的schema约束，无法表达的规则忽略
+func ruleSchema(schema map[string]interface{}, rl rule, value func(interface{}) interface{}) {
+	if len(rl.args) == 0 {
+		return
+	}
+	numeric := func(key string) {
+		if _, ok := toFloat64(rl.args[0]); ok {
+			schema[key] = rl.args[0]
+		}
+	}
+	switch rl.name {
+	case "MustMin", "MustMinValue":
+		numeric("minimum")
+	case "MustMax", "MustMaxValue":
+		numeric("maximum")
+	case "MustGreaterThanValue":
+		numeric("exclusiveMinimum")
+	case "MustLessThanValue":
+		numeric("exclusiveMaximum")
+	case "MustLength":
+		schema["minLength"] = rl.args[0]
+		schema["maxLength"] = rl.args[0]
+	case "MustLengthRange":
+		schema["minLength"] = rl.args[0]
+		schema["maxLength"] = rl.args[1]
+	case "MustMatch":
+		if re, ok := rl.args[0].(*regexp.Regexp); ok {
+			schema["pattern"] = re.String()
+		}
+	case "MustValues":
+		schema["enum"] = value(rl.args[0])
+	case "MustNotValues":
+		schema["not"] = map[string]interface{}{"enum": value(rl.args[0])}
+	}
+}
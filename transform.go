@@ -0,0 +1,81 @@
+package validator
+
+import (
+	"strings"
+	"unicode"
+)
+
+//参数预处理，在类型转换和规则校验之前执行
+type transformer struct {
+	name string
+	f    func(string) string
+}
+
+func (v *Validator) transform(key, value string) string {
+	for _, t := range v.transformMap[key] {
+		value = t.f(value)
+	}
+	return value
+}
+
+func (r *ruleSet) addTransform(name string, f func(string) string) RuleSet {
+	r.valid.own()
+	if r.setError != nil {
+		return r
+	}
+	if r.paramName == "" {
+		panic("unknown param name when set transform " + name)
+	}
+	r.valid.transformMap[r.paramName] = append(r.valid.transformMap[r.paramName], transformer{name: name, f: f})
+	p := r.valid.ApiParams[r.paramName]
+	p.Transforms = append(p.Transforms, name)
+	return r
+}
+
+func (r *ruleSet) Trim() RuleSet {
+	return r.addTransform("trim", strings.TrimSpace)
+}
+
+func (r *ruleSet) ToLower() RuleSet {
+	return r.addTransform("lower", strings.ToLower)
+}
+
+func (r *ruleSet) ToUpper() RuleSet {
+	return r.addTransform("upper", strings.ToUpper)
+}
+
+//连续空白合并为一个空格，并去掉首尾空白
+func (r *ruleSet) CollapseSpace() RuleSet {
+	return r.addTransform("collapse_space", func(s string) string {
+		return strings.Join(strings.Fields(s), " ")
+	})
+}
+
+//全角字符转半角
+func (r *ruleSet) ToHalfWidth() RuleSet {
+	return r.addTransform("half_width", func(s string) string {
+		return strings.Map(toHalfWidth, s)
+	})
+}
+
+//去掉控制字符和格式字符
+func (r *ruleSet) StripControl() RuleSet {
+	return r.addTransform("strip_control", func(s string) string {
+		return strings.Map(func(c rune) rune {
+			if unicode.IsControl(c) || unicode.Is(unicode.Cf, c) {
+				return -1
+			}
+			return c
+		}, s)
+	})
+}
+
+//自定义预处理，name用于文档展示
+func (r *ruleSet) Transform(name string, f func(string) string) RuleSet {
+	return r.addTransform(name, f)
+}
+
+//文档中展示的预处理步骤
+func (p *Params) TransformDoc() string {
+	return strings.Join(p.Transforms, ", ")
+}
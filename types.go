@@ -0,0 +1,111 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+//自定义类型的解析函数，解析后的值会保存到校验结果中，规则作用于解析后的值
+type TypeParser func(string) (interface{}, error)
+
+//自定义类型的格式化函数，Encode及文档中的默认值使用
+type TypeFormatter func(interface{}) (string, error)
+
+type customType struct {
+	name   string
+	parse  TypeParser
+	format TypeFormatter
+}
+
+var (
+	typesMu     sync.RWMutex
+	customTypes = map[string]*customType{}
+)
+
+//注册自定义参数类型，同名会覆盖，需在声明参数之前注册
+func RegisterType(name string, parse TypeParser, format TypeFormatter) {
+	if parse == nil {
+		panic("nil parser when register type " + name)
+	}
+	if format == nil {
+		format = func(value interface{}) (string, error) {
+			return fmt.Sprint(value), nil
+		}
+	}
+	typesMu.Lock()
+	defer typesMu.Unlock()
+	customTypes[name] = &customType{name: name, parse: parse, format: format}
+}
+
+func lookupType(name string) (*customType, bool) {
+	typesMu.RLock()
+	defer typesMu.RUnlock()
+	t, ok := customTypes[name]
+	return t, ok
+}
+
+//参数值必须是已注册的自定义类型，与MustSeparator一起声明时元素使用该类型，与声明顺序无关
+func (r *ruleSet) MustType(name string) RuleSet {
+	r.valid.own()
+	if r.setError != nil {
+		return r
+	}
+	if r.paramName == "" {
+		panic("unknown param name when set MustType")
+	}
+	t, ok := lookupType(name)
+	if !ok {
+		return r.fail(fmt.Errorf("type %s is not registered", name))
+	}
+	r.valid.customTypeMap[r.paramName] = t
+	p := r.valid.ApiParams[r.paramName]
+	if r.valid.typeMap[r.paramName] == reflect.Slice {
+		r.valid.elemTypeMap[r.paramName] = reflect.Interface
+		p.Type = "[]" + name
+	} else {
+		r.valid.typeMap[r.paramName] = reflect.Interface
+		p.Type = name
+	}
+	return r.typeChanged()
+}
+
+//按自定义类型解析参数值
+func (v *Validator) parseCustom(key, value string) (interface{}, error) {
+	t := v.customTypeMap[key]
+	parsed, err := t.parse(value)
+	if err != nil {
+		if Terr, ok := v.typeErrMap[key]; ok {
+			return nil, Terr
+		}
+		pErr := NewParamsError(key, value)
+		pErr.Args = []interface{}{t.name}
+		return nil, pErr.ErrMustType(v.CustomError)
+	}
+	return parsed, nil
+}
+
+//按参数类型转换默认值、可选值等声明中的值，自定义类型的字符串使用其解析函数
+func (v *Validator) convertParamValue(name string, value interface{}, kind reflect.Kind) (interface{}, error) {
+	t, ok := v.customTypeMap[name]
+	if kind != reflect.Interface || !ok {
+		return convertValue(value, kind)
+	}
+	s, isString := value.(string)
+	if !isString {
+		return value, nil
+	}
+	parsed, err := t.parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("value %q is not %s: %s", s, t.name, err.Error())
+	}
+	return parsed, nil
+}
+
+//参数类型的名称，自定义类型使用注册名
+func (v *Validator) kindName(name string, kind reflect.Kind) string {
+	if t, ok := v.customTypeMap[name]; ok && kind == reflect.Interface {
+		return t.name
+	}
+	return kind.String()
+}
@@ -1,9 +1,14 @@
 package validator
 
 import (
+	"fmt"
 	"net/url"
+	"reflect"
+	"regexp"
 	"strconv"
+	"strings"
 	"time"
+	"unicode"
 	"unicode/utf8"
 )
 
@@ -21,6 +26,17 @@ func isSlice(f ValidationFunc, k string, v interface{}, params url.Values, cus b
 	return is_slice, nil
 }
 
+//自定义规则未设置错误码时使用默认错误码
+func withDefaultCode(f ValidationFunc, code ErrorCode) ValidationFunc {
+	return func(k string, v interface{}, params url.Values, cus bool, args ...interface{}) error {
+		err := f(k, v, params, cus, args...)
+		if pErr, ok := err.(*ParamsError); ok && pErr.Code == "" {
+			pErr.Code = code
+		}
+		return err
+	}
+}
+
 //定义一些规则
 
 func mustLength(k string, v interface{}, params url.Values, cus bool, args ...interface{}) error {
@@ -39,30 +55,14 @@ func mustLength(k string, v interface{}, params url.Values, cus bool, args ...in
 	return nil
 }
 
+//MustMin和MustMax沿用原来的行为，值与边界无法比较时不校验，由Compile报告类型不匹配
 func mustMin(k string, v interface{}, params url.Values, cus bool, args ...interface{}) error {
 	ok, err := isSlice(mustMin, k, v, params, cus, args...)
 	if ok {
 		return err
 	}
-	min := args[0]
-	if vInt, ok := v.(int); ok {
-		if vInt < min.(int) {
-			pErr := NewParamsError(k, v)
-			pErr.Args = args
-			return pErr.ErrMustMin(cus)
-		}
-	} else if vInt32, ok := v.(int32); ok {
-		if vInt32 < int32(min.(int)) {
-			pErr := NewParamsError(k, v)
-			pErr.Args = args
-			return pErr.ErrMustMin(cus)
-		}
-	} else if vInt64, ok := v.(int64); ok {
-		if vInt64 < int64(min.(int)) {
-			pErr := NewParamsError(k, v)
-			pErr.Args = args
-			return pErr.ErrMustMin(cus)
-		}
+	if c, ok := compareBound(v, args...); ok && c < 0 {
+		return boundError(k, v, args...).ErrMustMin(cus)
 	}
 	return nil
 }
@@ -72,27 +72,165 @@ func mustMax(k string, v interface{}, params url.Values, cus bool, args ...inter
 	if ok {
 		return err
 	}
-	max := args[0]
-	if vInt, ok := v.(int); ok {
-		if vInt > max.(int) {
-			pErr := NewParamsError(k, v)
-			pErr.Args = args
-			return pErr.ErrMustMax(cus)
+	if c, ok := compareBound(v, args...); ok && c > 0 {
+		return boundError(k, v, args...).ErrMustMax(cus)
+	}
+	return nil
+}
+
+func mustMinValue(k string, v interface{}, params url.Values, cus bool, args ...interface{}) error {
+	ok, err := isSlice(mustMinValue, k, v, params, cus, args...)
+	if ok {
+		return err
+	}
+	if c, ok := compareBound(v, args...); !ok || c < 0 {
+		return boundError(k, v, args...).ErrMustMin(cus)
+	}
+	return nil
+}
+
+func mustMaxValue(k string, v interface{}, params url.Values, cus bool, args ...interface{}) error {
+	ok, err := isSlice(mustMaxValue, k, v, params, cus, args...)
+	if ok {
+		return err
+	}
+	if c, ok := compareBound(v, args...); !ok || c > 0 {
+		return boundError(k, v, args...).ErrMustMax(cus)
+	}
+	return nil
+}
+
+func mustGreaterThanValue(k string, v interface{}, params url.Values, cus bool, args ...interface{}) error {
+	ok, err := isSlice(mustGreaterThanValue, k, v, params, cus, args...)
+	if ok {
+		return err
+	}
+	if c, ok := compareBound(v, args...); !ok || c <= 0 {
+		return boundError(k, v, args...).ErrMustGreaterThanValue(cus)
+	}
+	return nil
+}
+
+func mustLessThanValue(k string, v interface{}, params url.Values, cus bool, args ...interface{}) error {
+	ok, err := isSlice(mustLessThanValue, k, v, params, cus, args...)
+	if ok {
+		return err
+	}
+	if c, ok := compareBound(v, args...); !ok || c >= 0 {
+		return boundError(k, v, args...).ErrMustLessThanValue(cus)
+	}
+	return nil
+}
+
+//时间类型的边界按layout格式化后放入错误参数
+func boundError(k string, v interface{}, args ...interface{}) *ParamsError {
+	pErr := NewParamsError(k, v)
+	if t, ok := args[0].(time.Time); ok {
+		layout := time.RFC3339
+		if len(args) > 1 && args[1].(string) != "" {
+			layout = args[1].(string)
 		}
-	} else if vInt32, ok := v.(int32); ok {
-		if vInt32 > int32(max.(int)) {
-			pErr := NewParamsError(k, v)
-			pErr.Args = args
-			return pErr.ErrMustMax(cus)
+		pErr.Args = []interface{}{t.Format(layout)}
+		return pErr
+	}
+	pErr.Args = args[:1]
+	return pErr
+}
+
+//比较v与边界args[0]，返回-1,0,1，类型无法比较时ok为false，边界规则按校验失败处理
+//时间边界时args[1]为layout，字符串值按layout解析，没有layout或解析失败时无法比较
+func compareBound(v interface{}, args ...interface{}) (int, bool) {
+	bound := args[0]
+	if t, ok := bound.(time.Time); ok {
+		var vTime time.Time
+		switch value := v.(type) {
+		case time.Time:
+			vTime = value
+		case string:
+			if len(args) < 2 || args[1].(string) == "" {
+				return 0, false
+			}
+			var err error
+			if vTime, err = time.Parse(args[1].(string), value); err != nil {
+				return 0, false
+			}
+		default:
+			return 0, false
 		}
-	} else if vInt64, ok := v.(int64); ok {
-		if vInt64 > int64(max.(int)) {
-			pErr := NewParamsError(k, v)
-			pErr.Args = args
-			return pErr.ErrMustMax(cus)
+		switch {
+		case vTime.Before(t):
+			return -1, true
+		case vTime.After(t):
+			return 1, true
 		}
+		return 0, true
 	}
-	return nil
+	vInt, vIsInt := toInt64(v)
+	bInt, bIsInt := toInt64(bound)
+	if vIsInt && bIsInt {
+		return compareInt64(vInt, bInt), true
+	}
+	vFloat, vIsNum := toFloat64(v)
+	bFloat, bIsNum := toFloat64(bound)
+	if !vIsNum || !bIsNum {
+		return 0, false
+	}
+	switch {
+	case vFloat < bFloat:
+		return -1, true
+	case vFloat > bFloat:
+		return 1, true
+	}
+	return 0, true
+}
+
+func compareInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func toInt64(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), true
+	case int8:
+		return int64(n), true
+	case int16:
+		return int64(n), true
+	case int32:
+		return int64(n), true
+	case int64:
+		return n, true
+	case uint8:
+		return int64(n), true
+	case uint16:
+		return int64(n), true
+	case uint32:
+		return int64(n), true
+	}
+	return 0, false
+}
+
+func toFloat64(v interface{}) (float64, bool) {
+	if n, ok := toInt64(v); ok {
+		return float64(n), true
+	}
+	switch n := v.(type) {
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	case uint:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	}
+	return 0, false
 }
 
 func mustLengthRange(k string, v interface{}, params url.Values, cus bool, args ...interface{}) error {
@@ -121,7 +259,7 @@ func mustValues(k string, v interface{}, params url.Values, cus bool, args ...in
 	values := args[0]
 
 	for _, value := range values.([]interface{}) {
-		if value == v {
+		if reflect.DeepEqual(value, v) {
 			allNotMatch = false
 			break
 		}
@@ -135,6 +273,170 @@ func mustValues(k string, v interface{}, params url.Values, cus bool, args ...in
 	return nil
 }
 
+func mustValuesIgnoreCase(k string, v interface{}, params url.Values, cus bool, args ...interface{}) error {
+	ok, err := isSlice(mustValuesIgnoreCase, k, v, params, cus, args...)
+	if ok {
+		return err
+	}
+	vString, ok := v.(string)
+	if !ok {
+		return nil
+	}
+	for _, value := range args[0].([]string) {
+		if strings.EqualFold(value, vString) {
+			return nil
+		}
+	}
+	pErr := NewParamsError(k, v)
+	pErr.Args = args
+	return pErr.ErrMustValues(cus)
+}
+
+func mustNotValues(k string, v interface{}, params url.Values, cus bool, args ...interface{}) error {
+	ok, err := isSlice(mustNotValues, k, v, params, cus, args...)
+	if ok {
+		return err
+	}
+	for _, value := range args[0].([]interface{}) {
+		if reflect.DeepEqual(value, v) {
+			pErr := NewParamsError(k, v)
+			pErr.Args = args
+			return pErr.ErrMustNotValues(cus)
+		}
+	}
+	return nil
+}
+
+func mustMatch(k string, v interface{}, params url.Values, cus bool, args ...interface{}) error {
+	ok, err := isSlice(mustMatch, k, v, params, cus, args...)
+	if ok {
+		return err
+	}
+	re := args[0].(*regexp.Regexp)
+	if vString, ok := v.(string); ok && !re.MatchString(vString) {
+		pErr := NewParamsError(k, v)
+		pErr.Args = []interface{}{re.String()}
+		return pErr.ErrMustMatch(cus)
+	}
+	return nil
+}
+
+func mustNotMatch(k string, v interface{}, params url.Values, cus bool, args ...interface{}) error {
+	ok, err := isSlice(mustNotMatch, k, v, params, cus, args...)
+	if ok {
+		return err
+	}
+	re := args[0].(*regexp.Regexp)
+	if vString, ok := v.(string); ok && re.MatchString(vString) {
+		pErr := NewParamsError(k, v)
+		pErr.Args = []interface{}{re.String()}
+		return pErr.ErrMustNotMatch(cus)
+	}
+	return nil
+}
+
+func mustPrefix(k string, v interface{}, params url.Values, cus bool, args ...interface{}) error {
+	ok, err := isSlice(mustPrefix, k, v, params, cus, args...)
+	if ok {
+		return err
+	}
+	if vString, ok := v.(string); ok && !strings.HasPrefix(vString, args[0].(string)) {
+		pErr := NewParamsError(k, v)
+		pErr.Args = args
+		return pErr.ErrMustPrefix(cus)
+	}
+	return nil
+}
+
+func mustSuffix(k string, v interface{}, params url.Values, cus bool, args ...interface{}) error {
+	ok, err := isSlice(mustSuffix, k, v, params, cus, args...)
+	if ok {
+		return err
+	}
+	if vString, ok := v.(string); ok && !strings.HasSuffix(vString, args[0].(string)) {
+		pErr := NewParamsError(k, v)
+		pErr.Args = args
+		return pErr.ErrMustSuffix(cus)
+	}
+	return nil
+}
+
+func mustContains(k string, v interface{}, params url.Values, cus bool, args ...interface{}) error {
+	ok, err := isSlice(mustContains, k, v, params, cus, args...)
+	if ok {
+		return err
+	}
+	if vString, ok := v.(string); ok && !strings.Contains(vString, args[0].(string)) {
+		pErr := NewParamsError(k, v)
+		pErr.Args = args
+		return pErr.ErrMustContains(cus)
+	}
+	return nil
+}
+
+//字符类规则，每个字符都需满足allow
+type charClass struct {
+	allow func(rune) bool
+	err   func(*ParamsError, bool) *ParamsError
+}
+
+var (
+	asciiClass = &charClass{
+		allow: func(c rune) bool { return c <= unicode.MaxASCII },
+		err:   (*ParamsError).ErrMustASCII,
+	}
+	alphanumericClass = &charClass{
+		allow: func(c rune) bool { return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' },
+		err:   (*ParamsError).ErrMustAlphanumeric,
+	}
+	digitsClass = &charClass{
+		allow: func(c rune) bool { return c >= '0' && c <= '9' },
+		err:   (*ParamsError).ErrMustDigits,
+	}
+	hanClass = &charClass{
+		allow: func(c rune) bool { return unicode.Is(unicode.Han, c) },
+		err:   (*ParamsError).ErrMustHan,
+	}
+	noEmojiClass = &charClass{
+		allow: func(c rune) bool { return !isEmoji(c) },
+		err:   (*ParamsError).ErrMustNoEmoji,
+	}
+	noControlClass = &charClass{
+		allow: func(c rune) bool { return !unicode.IsControl(c) && !unicode.Is(unicode.Cf, c) },
+		err:   (*ParamsError).ErrMustNoControl,
+	}
+)
+
+func isEmoji(c rune) bool {
+	switch {
+	case c >= 0x1F000 && c <= 0x1FAFF:
+		return true
+	case c >= 0x2600 && c <= 0x27BF:
+		return true
+	case c >= 0x2B00 && c <= 0x2BFF:
+		return true
+	case c == 0x200D || c == 0xFE0F || c == 0x20E3:
+		return true
+	}
+	return false
+}
+
+func mustCharClass(k string, v interface{}, params url.Values, cus bool, args ...interface{}) error {
+	ok, err := isSlice(mustCharClass, k, v, params, cus, args...)
+	if ok {
+		return err
+	}
+	class := args[0].(*charClass)
+	if vString, ok := v.(string); ok {
+		for _, c := range vString {
+			if !class.allow(c) {
+				return class.err(NewParamsError(k, v), cus)
+			}
+		}
+	}
+	return nil
+}
+
 func mustTimeLayout(k string, v interface{}, params url.Values, cus bool, args ...interface{}) error {
 	ok, err := isSlice(mustTimeLayout, k, v, params, cus, args...)
 	if ok {
@@ -192,3 +494,74 @@ func mustLargeThan(k string, v interface{}, params url.Values, cus bool, args ..
 	}
 	return nil
 }
+
+//将声明的值转换为参数的类型
+func convertValue(value interface{}, kind reflect.Kind) (interface{}, error) {
+	switch kind {
+	case reflect.Int, reflect.Int64:
+		var n int64
+		switch vt := value.(type) {
+		case string:
+			var err error
+			if n, err = strconv.ParseInt(vt, 10, 64); err != nil {
+				return nil, fmt.Errorf("value %q is not %s", vt, kind)
+			}
+		default:
+			rv := reflect.ValueOf(value)
+			switch rv.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				n = rv.Int()
+			case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+				n = int64(rv.Uint())
+			default:
+				return nil, fmt.Errorf("value %v(%T) is not %s", value, value, kind)
+			}
+		}
+		if kind == reflect.Int {
+			return int(n), nil
+		}
+		return n, nil
+	case reflect.Float64:
+		if vString, ok := value.(string); ok {
+			f, err := strconv.ParseFloat(vString, 64)
+			if err != nil {
+				return nil, fmt.Errorf("value %q is not %s", vString, kind)
+			}
+			return f, nil
+		}
+		if f, ok := toFloat64(value); ok {
+			return f, nil
+		}
+		return nil, fmt.Errorf("value %v(%T) is not %s", value, value, kind)
+	case reflect.Bool:
+		switch vt := value.(type) {
+		case bool:
+			return vt, nil
+		case string:
+			b, err := strconv.ParseBool(vt)
+			if err != nil {
+				return nil, fmt.Errorf("value %q is not %s", vt, kind)
+			}
+			return b, nil
+		}
+		return nil, fmt.Errorf("value %v(%T) is not %s", value, value, kind)
+	case reflect.String:
+		switch vt := value.(type) {
+		case string:
+			return vt, nil
+		case fmt.Stringer:
+			return vt.String(), nil
+		}
+		if rv := reflect.ValueOf(value); rv.Kind() == reflect.String {
+			return rv.String(), nil
+		}
+		if _, ok := toFloat64(value); ok {
+			return fmt.Sprint(value), nil
+		}
+		if b, ok := value.(bool); ok {
+			return strconv.FormatBool(b), nil
+		}
+		return nil, fmt.Errorf("value %v(%T) is not %s", value, value, kind)
+	}
+	return value, nil
+}
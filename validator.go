@@ -1,29 +1,88 @@
 package validator
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 )
 
 type RuleSet interface {
 	Description(string) RuleSet
+	Label(string) RuleSet
+	LocaleLabel(string, string) RuleSet
+	Sensitive() RuleSet
+	DefaultFunc(func(ValueGetter) interface{}) RuleSet
+	AllowEmpty() RuleSet
+	CheckEmpty() RuleSet
+	NotEmptyIfPresent() RuleSet
+	Trim() RuleSet
+	ToLower() RuleSet
+	ToUpper() RuleSet
+	CollapseSpace() RuleSet
+	ToHalfWidth() RuleSet
+	StripControl() RuleSet
+	Transform(string, func(string) string) RuleSet
 	Require(bool) RuleSet
 	MustLength(int) RuleSet
 	MustInt() RuleSet
 	MustInt64() RuleSet
+	MustFloat64() RuleSet
 	MustBool() RuleSet
+	MustType(string) RuleSet
 	MustMin(int) RuleSet
 	MustMax(int) RuleSet
+	MustMinValue(interface{}) RuleSet
+	MustMaxValue(interface{}) RuleSet
+	MustGreaterThanValue(interface{}) RuleSet
+	MustLessThanValue(interface{}) RuleSet
 	MustSeparator(string, reflect.Kind) RuleSet
 	MustLengthRange(int, int) RuleSet
 	MustValues([]interface{}) RuleSet
+	MustValuesIgnoreCase([]string) RuleSet
+	MustNotValues([]interface{}) RuleSet
+	MustEnum(EnumValues) RuleSet
 	MustTimeLayout(string) RuleSet
 	MustLessThan(string) RuleSet
 	MustLargeThan(string) RuleSet
+	MustMatch(string) RuleSet
+	MustNotMatch(string) RuleSet
+	MustPrefix(string) RuleSet
+	MustSuffix(string) RuleSet
+	MustContains(string) RuleSet
+	MustASCII() RuleSet
+	MustAlphanumeric() RuleSet
+	MustDigits() RuleSet
+	MustHan() RuleSet
+	MustNoEmoji() RuleSet
+	MustNoControl() RuleSet
+	MustEmail() RuleSet
+	MustURL() RuleSet
+	MustIP() RuleSet
+	MustIPv4() RuleSet
+	MustIPv6() RuleSet
+	MustCIDR() RuleSet
+	MustHostname() RuleSet
+	MustPort() RuleSet
+	MustMAC() RuleSet
+	MustUUID() RuleSet
+	MustHex() RuleSet
+	MustBase64() RuleSet
+	MustSemver() RuleSet
+	MustMobileCN() RuleSet
+	MustIDCardCN() RuleSet
+	MustCreditCodeCN() RuleSet
+	MustPostcodeCN() RuleSet
+	MustBankCard() RuleSet
+	MustPasswordPolicy(*PasswordPolicy) RuleSet
+	MustNotContainWords(*WordDict) RuleSet
 	MustFunc(ValidationFunc, []interface{}) RuleSet
+	MustRule(Rule) RuleSet
 }
 
 const (
@@ -31,28 +90,60 @@ const (
 	ValidateTag = "validate"
 )
 
+//Go枚举类型提供所有可选值
+type EnumValues interface {
+	EnumValues() []interface{}
+}
+
 type ValidationFunc func(string, interface{}, url.Values, bool, ...interface{}) error
 
 type Params struct {
-	Type        string
-	Description string
-	Require     bool
-	Rules       []rule
+	Type              string
+	Description       string
+	Label             string
+	Labels            map[string]string
+	Sensitive         bool
+	Transforms        []string
+	AllowEmpty        bool
+	CheckEmpty        bool
+	NotEmptyIfPresent bool
+	Default           interface{}
+	HasDefault        bool
+	DynamicDefault    bool
+	Source            string
+	Require           bool
+	Rules             []rule
 }
 
 type Validator struct {
+	Name                string
 	IgnoreUnknownParams bool
 	CustomError         bool
+	Locale              string
+	NullToken           string
 	ApiParams           map[string]*Params
 	requireParams       []string
 	requireUrlParams    []string
+	forbidParams        map[string]bool
+	profiles            map[string][]profileOp
 	splitChar           string
+	separatorMap        map[string]string
+	customTypeMap       map[string]*customType
 	ruleMap             map[string][]rule
 	valueMap            map[string]interface{}
+	parsedMap           map[string]interface{}
+	presenceMap         map[string]Presence
 	defaultValueMap     map[string]interface{}
+	defaultFuncs        []defaultFunc
+	computed            []computedParam
 	typeMap             map[string]reflect.Kind
 	elemTypeMap         map[string]reflect.Kind
+	timeLayoutMap       map[string]string
+	formatMap           map[string]*format
+	transformMap        map[string][]transformer
 	typeErrMap          map[string]error
+	sharing             *int32
+	declErrors          []error
 }
 
 func NewValidator() *Validator {
@@ -60,33 +151,54 @@ func NewValidator() *Validator {
 	v.IgnoreUnknownParams = true
 	v.ApiParams = make(map[string]*Params)
 	v.ruleMap = make(map[string][]rule)
+	v.forbidParams = make(map[string]bool)
 	v.typeMap = make(map[string]reflect.Kind)
 	v.elemTypeMap = make(map[string]reflect.Kind)
-	v.valueMap = make(map[string]interface{})
+	v.timeLayoutMap = make(map[string]string)
+	v.formatMap = make(map[string]*format)
+	v.transformMap = make(map[string][]transformer)
+	v.resetResult()
 	v.defaultValueMap = make(map[string]interface{})
+	v.separatorMap = make(map[string]string)
+	v.customTypeMap = make(map[string]*customType)
 	return v
 }
 
 func Validate(params url.Values, v *Validator) error {
+	return ValidateContext(context.Background(), params, v)
+}
+
+//带上下文的校验，ctx传递给MustRule声明的规则，ctx结束时返回ctx.Err()
+func ValidateContext(ctx context.Context, params url.Values, v *Validator) error {
+	return v.labelError(validate(ctx, params, v))
+}
+
+func validate(ctx context.Context, params url.Values, v *Validator) error {
+	v.resetResult()
 	for _, p := range v.requireParams {
 		if values, ok := params[p]; !ok {
-			Perr := new(ParamsError)
-			Perr.Key = p
+			Perr := NewParamsError(p, nil)
 			Perr.ErrRequireParam(v.CustomError)
 			return Perr
-		} else if values[0] == "" {
-			Perr := new(ParamsError)
-			Perr.Key = p
+		} else if v.isEmpty(v.transform(p, values[0])) && !v.ApiParams[p].AllowEmpty {
+			Perr := NewParamsError(p, nil)
 			Perr.ErrRequireNotNull(v.CustomError)
 			return Perr
 		}
 	}
-	for key, value := range params {
-		if value[0] == "" {
+	for key, values := range params {
+		value := v.transform(key, values[0])
+		if v.forbidParams[key] {
+			return NewParamsError(key, value).ErrForbiddenParam(v.CustomError)
+		}
+		if skip, err := v.presenceCheck(key, value); skip {
+			if err != nil {
+				return err
+			}
 			continue
 		}
 		if rules, ok := v.ruleMap[key]; ok {
-			err := v.valueCheck(key, value[0])
+			err := v.valueCheck(key, value)
 			if err != nil {
 				return err
 			}
@@ -94,9 +206,9 @@ func Validate(params url.Values, v *Validator) error {
 				if rule.f != nil {
 					var err error
 					if valueInterface, ok := v.valueMap[key]; ok {
-						err = rule.f(key, valueInterface, params, v.CustomError, rule.args...)
+						err = rule.f(key, valueInterface, params, v.CustomError, v.ruleArgs(key, rule)...)
 					} else {
-						err = rule.f(key, value[0], params, v.CustomError, rule.args...)
+						err = rule.f(key, value, params, v.CustomError, v.ruleArgs(key, rule)...)
 					}
 					if err != nil {
 						return err
@@ -104,31 +216,48 @@ func Validate(params url.Values, v *Validator) error {
 				}
 			}
 		} else {
-			Perr := NewParamsError(key, value[0])
+			Perr := NewParamsError(key, value)
 			Perr.ErrUnknownParam(v.CustomError)
 			return Perr
 		}
 	}
-	return nil
+	if err := v.applyDefaults(); err != nil {
+		return err
+	}
+	return v.runContextRules(ctx, params)
 }
 
 func UrlValidator(params map[string]string, v *Validator) error {
+	return UrlValidatorContext(context.Background(), params, v)
+}
+
+func UrlValidatorContext(ctx context.Context, params map[string]string, v *Validator) error {
+	return v.labelError(urlValidate(ctx, params, v))
+}
+
+func urlValidate(ctx context.Context, params map[string]string, v *Validator) error {
+	v.resetResult()
 	for _, p := range v.requireUrlParams {
 		if value, ok := params[p]; !ok {
-			Perr := new(ParamsError)
-			Perr.Key = p
+			Perr := NewParamsError(p, nil)
 			Perr.ErrRequireParam(v.CustomError)
 			return Perr
-		} else if value == "" {
-			Perr := new(ParamsError)
-			Perr.Key = p
+		} else if v.isEmpty(v.transform(p, value)) && !v.ApiParams[p].AllowEmpty {
+			Perr := NewParamsError(p, nil)
 			Perr.ErrRequireNotNull(v.CustomError)
 			return Perr
 		}
 	}
 
 	for key, value := range params {
-		if value == "" {
+		value = v.transform(key, value)
+		if v.forbidParams[key] {
+			return NewParamsError(key, value).ErrForbiddenParam(v.CustomError)
+		}
+		if skip, err := v.presenceCheck(key, value); skip {
+			if err != nil {
+				return err
+			}
 			continue
 		}
 		if rules, ok := v.ruleMap[key]; ok {
@@ -140,9 +269,9 @@ func UrlValidator(params map[string]string, v *Validator) error {
 				if rule.f != nil {
 					var err error
 					if valueInterface, ok := v.valueMap[key]; ok {
-						err = rule.f(key, valueInterface, nil, v.CustomError, rule.args...)
+						err = rule.f(key, valueInterface, nil, v.CustomError, v.ruleArgs(key, rule)...)
 					} else {
-						err = rule.f(key, value, nil, v.CustomError, rule.args...)
+						err = rule.f(key, value, nil, v.CustomError, v.ruleArgs(key, rule)...)
 					}
 					if err != nil {
 						return err
@@ -155,7 +284,14 @@ func UrlValidator(params map[string]string, v *Validator) error {
 			return Perr
 		}
 	}
-	return nil
+	if err := v.applyDefaults(); err != nil {
+		return err
+	}
+	raw := make(url.Values, len(params))
+	for key, value := range params {
+		raw.Set(key, value)
+	}
+	return v.runContextRules(ctx, raw)
 }
 
 type ruleSet struct {
@@ -169,29 +305,112 @@ type rule struct {
 	f      ValidationFunc
 	args   []interface{}
 	errMsg error
+	doc    string
+	//args[0]为其他参数名
+	fieldArg bool
+	//声明规则的方法名
+	name string
+	//MustRule声明的上下文规则，在其他规则之后执行
+	ctxRule Rule
+	//MustValues、MustNotValues声明的原始值，参数类型变化时重新转换
+	values []interface{}
 }
 
 var _ RuleSet = new(ruleSet)
 
+//深拷贝，拷贝后的Validator与原Validator互不影响
 func (v *Validator) Clone() *Validator {
-	valid := Validator{
-		IgnoreUnknownParams: v.IgnoreUnknownParams,
-		CustomError:         v.CustomError,
-		ApiParams:           v.ApiParams,
-		requireParams:       v.requireParams,
-		requireUrlParams:    v.requireUrlParams,
-		splitChar:           v.splitChar,
-		ruleMap:             v.ruleMap,
-		valueMap:            make(map[string]interface{}),
-		defaultValueMap:     v.defaultValueMap,
-		typeMap:             v.typeMap,
-		elemTypeMap:         v.elemTypeMap,
-		typeErrMap:          v.typeErrMap,
+	valid := v.derived()
+	valid.copyDeclarations()
+	return valid
+}
+
+//写时复制的派生，派生前的声明共享，任意一方修改声明时才进行拷贝
+func (v *Validator) Derive() *Validator {
+	if v.sharing == nil {
+		v.sharing = new(int32)
 	}
+	atomic.StoreInt32(v.sharing, 1)
+	return v.derived()
+}
+
+func (v *Validator) derived() *Validator {
+	valid := *v
+	valid.resetResult()
 	return &valid
 }
 
+//清空上一次校验的结果，校验器可被重复使用
+func (v *Validator) resetResult() {
+	v.valueMap = make(map[string]interface{})
+	v.parsedMap = make(map[string]interface{})
+	v.presenceMap = make(map[string]Presence)
+}
+
+//修改声明前调用，声明与其他Validator共享时先拷贝
+func (v *Validator) own() {
+	if v.sharing != nil && atomic.LoadInt32(v.sharing) == 1 {
+		v.copyDeclarations()
+	}
+}
+
+func (v *Validator) copyDeclarations() {
+	apiParams := make(map[string]*Params, len(v.ApiParams))
+	for name, p := range v.ApiParams {
+		apiParams[name] = p.clone()
+	}
+	v.ApiParams = apiParams
+	ruleMap := make(map[string][]rule, len(v.ruleMap))
+	for name, rules := range v.ruleMap {
+		ruleMap[name] = append([]rule(nil), rules...)
+	}
+	v.ruleMap = ruleMap
+	transformMap := make(map[string][]transformer, len(v.transformMap))
+	for name, ts := range v.transformMap {
+		transformMap[name] = append([]transformer(nil), ts...)
+	}
+	v.transformMap = transformMap
+	v.requireParams = append([]string(nil), v.requireParams...)
+	v.requireUrlParams = append([]string(nil), v.requireUrlParams...)
+	v.declErrors = append([]error(nil), v.declErrors...)
+	v.forbidParams = copyMap(v.forbidParams)
+	v.defaultValueMap = copyMap(v.defaultValueMap)
+	v.defaultFuncs = append([]defaultFunc(nil), v.defaultFuncs...)
+	v.separatorMap = copyMap(v.separatorMap)
+	v.customTypeMap = copyMap(v.customTypeMap)
+	v.computed = append([]computedParam(nil), v.computed...)
+	v.typeMap = copyMap(v.typeMap)
+	v.elemTypeMap = copyMap(v.elemTypeMap)
+	v.timeLayoutMap = copyMap(v.timeLayoutMap)
+	v.formatMap = copyMap(v.formatMap)
+	v.typeErrMap = copyMap(v.typeErrMap)
+	v.profiles = copyMap(v.profiles)
+	v.sharing = new(int32)
+}
+
+func copyMap[K comparable, V any](m map[K]V) map[K]V {
+	if m == nil {
+		return nil
+	}
+	copied := make(map[K]V, len(m))
+	for k, value := range m {
+		copied[k] = value
+	}
+	return copied
+}
+
+func (p *Params) clone() *Params {
+	cp := *p
+	cp.Rules = append([]rule(nil), p.Rules...)
+	cp.Transforms = append([]string(nil), p.Transforms...)
+	if p.Labels != nil {
+		cp.Labels = copyMap(p.Labels)
+	}
+	return &cp
+}
+
 func (v *Validator) NewParam(paramName string, value ...interface{}) RuleSet {
+	v.own()
 	p := new(Params)
 	p.Type = reflect.String.String()
 	v.ApiParams[paramName] = p
@@ -199,14 +418,16 @@ func (v *Validator) NewParam(paramName string, value ...interface{}) RuleSet {
 	r.paramName = paramName
 	r.valid = v
 	r.valid.typeMap[paramName] = reflect.String
+	r.valid.ruleMap[paramName] = append(r.valid.ruleMap[paramName], *new(rule))
 	if len(value) == 1 {
 		r.valid.defaultValueMap[paramName] = value[0]
+		return r.coerceDefault()
 	}
-	r.valid.ruleMap[paramName] = append(r.valid.ruleMap[paramName], *new(rule))
 	return r
 }
 
 func (v *Validator) NewUrlParam(paramName string, value ...interface{}) RuleSet {
+	v.own()
 	p := new(Params)
 	p.Type = reflect.String.String()
 	v.ApiParams[paramName] = p
@@ -215,126 +436,57 @@ func (v *Validator) NewUrlParam(paramName string, value ...interface{}) RuleSet
 	r.is_url_param = true
 	r.valid = v
 	r.valid.typeMap[paramName] = reflect.String
+	r.valid.ruleMap[paramName] = append(r.valid.ruleMap[paramName], *new(rule))
 	if len(value) == 1 {
 		r.valid.defaultValueMap[paramName] = value[0]
+		return r.coerceDefault()
 	}
-	r.valid.ruleMap[paramName] = append(r.valid.ruleMap[paramName], *new(rule))
 	return r
 }
 
-func (v *Validator) SetCustomError() *Validator {
-	v.CustomError = true
-	return v
+//参数显示名，优先使用当前Locale的显示名，未设置时使用参数名
+func (v *Validator) ParamLabel(paramName string) string {
+	p, ok := v.ApiParams[paramName]
+	if !ok {
+		return paramName
+	}
+	if label, ok := p.Labels[v.Locale]; ok && v.Locale != "" {
+		return label
+	}
+	if p.Label != "" {
+		return p.Label
+	}
+	return paramName
 }
 
-func (v *Validator) ValuesToStruct(dst interface{}) error {
-	vl := reflect.ValueOf(dst)
-	if vl.Kind() != reflect.Ptr || vl.Elem().Kind() != reflect.Struct {
-		return NewTextError("interface must be a pointer to struct")
-	}
-	vl = vl.Elem()
-	t := vl.Type()
-
-	for i := 0; i < t.NumField(); i++ {
-		if vl.Field(i).Kind() == reflect.Struct {
-			st := vl.Field(i).Type()
-			sv := vl.Field(i)
-			for j := 0; j < st.NumField(); j++ {
-				paramName := st.Field(j).Tag.Get(ValidTag)
-				if _, ok := v.valueMap[paramName]; ok {
-					switch v.typeMap[paramName] {
-					case reflect.Int:
-						sv.Field(j).SetInt(int64(v.valueMap[paramName].(int)))
-					case reflect.Int64:
-						sv.Field(j).SetInt(v.valueMap[paramName].(int64))
-					case reflect.Bool:
-						sv.Field(j).SetBool(v.valueMap[paramName].(bool))
-					case reflect.String:
-						sv.Field(j).SetString(v.valueMap[paramName].(string))
-					case reflect.Slice:
-						slicev := reflect.MakeSlice(sv.Field(j).Type(), 0, 0)
-						vInterface := v.valueMap[paramName].([]interface{})
-						for _, sliceV := range vInterface {
-							slicev = reflect.Append(slicev, reflect.ValueOf(sliceV))
-						}
-						sv.Field(i).Set(slicev)
-					}
-				} else if _, ok := v.defaultValueMap[paramName]; ok {
-					switch v.typeMap[paramName] {
-					case reflect.Int:
-						sv.Field(j).SetInt(int64(v.defaultValueMap[paramName].(int)))
-					case reflect.Int64:
-						sv.Field(j).SetInt(v.defaultValueMap[paramName].(int64))
-					case reflect.Bool:
-						sv.Field(j).SetBool(v.defaultValueMap[paramName].(bool))
-					case reflect.String:
-						sv.Field(j).SetString(v.defaultValueMap[paramName].(string))
-					case reflect.Slice:
-						slicev := reflect.MakeSlice(sv.Field(j).Type(), 0, 0)
-						vInterface := v.defaultValueMap[paramName].([]interface{})
-						for _, sliceV := range vInterface {
-							slicev = reflect.Append(slicev, reflect.ValueOf(sliceV))
-						}
-						sv.Field(i).Set(slicev)
-					}
-				}
-			}
-		} else {
-			paramName := t.Field(i).Tag.Get(ValidTag)
-			fieldv := vl.Field(i)
-			if fieldv.Kind() == reflect.Ptr && !fieldv.IsNil() {
-				fieldv = fieldv.Elem()
-			}
-			if _, ok := v.valueMap[paramName]; ok {
-				if fieldv.Kind() == reflect.Ptr && fieldv.CanSet() {
-					//对空指针进行初始化，暂时用临时变量保存
-					fieldv.Set(reflect.New(fieldv.Type().Elem()))
-					fieldv = fieldv.Elem()
-				}
-				switch v.typeMap[paramName] {
-				case reflect.Int:
-					fieldv.SetInt(int64(v.valueMap[paramName].(int)))
-				case reflect.Int64:
-					fieldv.SetInt(v.valueMap[paramName].(int64))
-				case reflect.Bool:
-					fieldv.SetBool(v.valueMap[paramName].(bool))
-				case reflect.String:
-					fieldv.SetString(v.valueMap[paramName].(string))
-				case reflect.Slice:
-					sv := reflect.MakeSlice(fieldv.Type(), 0, 0)
-					vInterface := v.valueMap[paramName].([]interface{})
-					for _, sliceV := range vInterface {
-						sv = reflect.Append(sv, reflect.ValueOf(sliceV))
-					}
-					fieldv.Set(sv)
-				}
-			} else if _, ok := v.defaultValueMap[paramName]; ok {
-				if fieldv.Kind() == reflect.Ptr && fieldv.CanSet() {
-					//对空指针进行初始化，暂时用临时变量保存
-					fieldv.Set(reflect.New(fieldv.Type().Elem()))
-					fieldv = fieldv.Elem()
-				}
-				switch v.typeMap[paramName] {
-				case reflect.Int:
-					fieldv.SetInt(int64(v.defaultValueMap[paramName].(int)))
-				case reflect.Int64:
-					fieldv.SetInt(v.defaultValueMap[paramName].(int64))
-				case reflect.Bool:
-					fieldv.SetBool(v.defaultValueMap[paramName].(bool))
-				case reflect.String:
-					fieldv.SetString(v.defaultValueMap[paramName].(string))
-				case reflect.Slice:
-					sv := reflect.MakeSlice(fieldv.Type(), 0, 0)
-					vInterface := v.defaultValueMap[paramName].([]interface{})
-					for _, sliceV := range vInterface {
-						sv = reflect.Append(sv, reflect.ValueOf(sliceV))
-					}
-					fieldv.Set(sv)
-				}
-			}
+func (v *Validator) labelError(err error) error {
+	switch e := err.(type) {
+	case *ParamsError:
+		v.labelParamsError(e)
+	case ParamsErrors:
+		for _, pErr := range e {
+			v.labelParamsError(pErr)
 		}
 	}
-	return nil
+	return err
+}
+
+//设置显示名，敏感参数去掉参数值
+func (v *Validator) labelParamsError(pErr *ParamsError) {
+	if p, ok := v.ApiParams[pErr.Key]; ok && p.Sensitive {
+		pErr.Value = nil
+	}
+	pErr.SetLabel(v.ParamLabel(pErr.Key))
+}
+
+func (v *Validator) SetLocale(locale string) *Validator {
+	v.Locale = locale
+	return v
+}
+
+func (v *Validator) SetCustomError() *Validator {
+	v.CustomError = true
+	return v
 }
 
 func (v *Validator) valueCheck(key, value string) error {
@@ -347,7 +499,7 @@ func (v *Validator) valueCheck(key, value string) error {
 				if Terr, ok := v.typeErrMap[key]; ok {
 					return Terr
 				}
-				return fmt.Errorf("参数[%s]格式错误,参数值必须是int类型", key)
+				return NewParamsError(key, value).ErrMustInt(v.CustomError)
 			}
 		case reflect.Int64:
 			v.valueMap[key], err = strconv.ParseInt(value, 10, 64)
@@ -355,22 +507,37 @@ func (v *Validator) valueCheck(key, value string) error {
 				if Terr, ok := v.typeErrMap[key]; ok {
 					return Terr
 				}
-				return fmt.Errorf("参数[%s]格式错误,参数值必须是int64类型", key)
+				return NewParamsError(key, value).ErrMustInt64(v.CustomError)
+			}
+		case reflect.Float64:
+			v.valueMap[key], err = strconv.ParseFloat(value, 64)
+			if err != nil {
+				if Terr, ok := v.typeErrMap[key]; ok {
+					return Terr
+				}
+				return NewParamsError(key, value).ErrMustFloat64(v.CustomError)
 			}
 		case reflect.Bool:
 			if valueBool, err := strconv.ParseBool(value); err != nil {
 				if Terr, ok := v.typeErrMap[key]; ok {
 					return Terr
 				}
-				return fmt.Errorf("参数[%s]格式错误,参数值必须是bool类型", key)
+				return NewParamsError(key, value).ErrMustBool(v.CustomError)
 			} else {
 				v.valueMap[key] = valueBool
 			}
 		case reflect.String:
 			v.valueMap[key] = value
+			if err := v.formatCheck(key, value, true); err != nil {
+				return err
+			}
+		case reflect.Interface:
+			if v.valueMap[key], err = v.parseCustom(key, value); err != nil {
+				return err
+			}
 		case reflect.Slice:
 			var sliceInterface []interface{}
-			sliceString := strings.Split(value, v.splitChar)
+			sliceString := strings.Split(value, v.separator(key))
 			switch v.elemTypeMap[key] {
 			case reflect.Int:
 				for _, vString := range sliceString {
@@ -379,7 +546,7 @@ func (v *Validator) valueCheck(key, value string) error {
 						if Terr, ok := v.typeErrMap[key]; ok {
 							return Terr
 						}
-						return fmt.Errorf("参数[%s]格式错误,参数值必须是int类型", key)
+						return NewParamsError(key, value).ErrMustInt(v.CustomError)
 					}
 					sliceInterface = append(sliceInterface, vInt)
 				}
@@ -390,25 +557,47 @@ func (v *Validator) valueCheck(key, value string) error {
 						if Terr, ok := v.typeErrMap[key]; ok {
 							return Terr
 						}
-						return fmt.Errorf("参数[%s]格式错误,参数值必须是int64类型", key)
+						return NewParamsError(key, value).ErrMustInt64(v.CustomError)
 					}
 					sliceInterface = append(sliceInterface, vInt64)
 				}
+			case reflect.Float64:
+				for _, vString := range sliceString {
+					vFloat64, err := strconv.ParseFloat(vString, 64)
+					if err != nil {
+						if Terr, ok := v.typeErrMap[key]; ok {
+							return Terr
+						}
+						return NewParamsError(key, value).ErrMustFloat64(v.CustomError)
+					}
+					sliceInterface = append(sliceInterface, vFloat64)
+				}
 			case reflect.Bool:
 				for _, vString := range sliceString {
 					if vBool, err := strconv.ParseBool(vString); err != nil {
 						if Terr, ok := v.typeErrMap[key]; ok {
 							return Terr
 						}
-						return fmt.Errorf("参数[%s]格式错误,参数值必须是bool类型", key)
+						return NewParamsError(key, value).ErrMustBool(v.CustomError)
 					} else {
 						sliceInterface = append(sliceInterface, vBool)
 					}
 				}
 			case reflect.String:
 				for _, vString := range sliceString {
+					if err := v.formatCheck(key, vString, false); err != nil {
+						return err
+					}
 					sliceInterface = append(sliceInterface, vString)
 				}
+			case reflect.Interface:
+				for _, vString := range sliceString {
+					parsed, err := v.parseCustom(key, vString)
+					if err != nil {
+						return err
+					}
+					sliceInterface = append(sliceInterface, parsed)
+				}
 			}
 			v.valueMap[key] = sliceInterface
 		default:
@@ -420,11 +609,36 @@ func (v *Validator) valueCheck(key, value string) error {
 }
 
 func (r *ruleSet) Description(description string) RuleSet {
+	r.valid.own()
 	r.valid.ApiParams[r.paramName].Description = description
 	return r
 }
 
+func (r *ruleSet) Label(label string) RuleSet {
+	r.valid.own()
+	r.valid.ApiParams[r.paramName].Label = label
+	return r
+}
+
+func (r *ruleSet) LocaleLabel(locale, label string) RuleSet {
+	r.valid.own()
+	p := r.valid.ApiParams[r.paramName]
+	if p.Labels == nil {
+		p.Labels = make(map[string]string)
+	}
+	p.Labels[locale] = label
+	return r
+}
+
+//敏感参数的值不会出现在错误中
+func (r *ruleSet) Sensitive() RuleSet {
+	r.valid.own()
+	r.valid.ApiParams[r.paramName].Sensitive = true
+	return r
+}
+
 func (r *ruleSet) Require(require bool) RuleSet {
+	r.valid.own()
 	if r.setError != nil {
 		return r
 	}
@@ -445,6 +659,7 @@ func (r *ruleSet) Require(require bool) RuleSet {
 }
 
 func (r *ruleSet) MustInt() RuleSet {
+	r.valid.own()
 	if r.setError != nil {
 		return r
 	}
@@ -454,10 +669,11 @@ func (r *ruleSet) MustInt() RuleSet {
 
 	r.valid.ApiParams[r.paramName].Type = reflect.Int.String()
 	r.valid.typeMap[r.paramName] = reflect.Int
-	return r
+	return r.typeChanged()
 }
 
 func (r *ruleSet) MustInt64() RuleSet {
+	r.valid.own()
 	if r.setError != nil {
 		return r
 	}
@@ -467,10 +683,25 @@ func (r *ruleSet) MustInt64() RuleSet {
 
 	r.valid.ApiParams[r.paramName].Type = reflect.Int64.String()
 	r.valid.typeMap[r.paramName] = reflect.Int64
-	return r
+	return r.typeChanged()
+}
+
+func (r *ruleSet) MustFloat64() RuleSet {
+	r.valid.own()
+	if r.setError != nil {
+		return r
+	}
+	if r.paramName == "" {
+		panic("unknown param name when set MustFloat64")
+	}
+
+	r.valid.ApiParams[r.paramName].Type = reflect.Float64.String()
+	r.valid.typeMap[r.paramName] = reflect.Float64
+	return r.typeChanged()
 }
 
 func (r *ruleSet) MustBool() RuleSet {
+	r.valid.own()
 	if r.setError != nil {
 		return r
 	}
@@ -480,20 +711,40 @@ func (r *ruleSet) MustBool() RuleSet {
 
 	r.valid.ApiParams[r.paramName].Type = reflect.Bool.String()
 	r.valid.typeMap[r.paramName] = reflect.Bool
-	return r
+	return r.typeChanged()
 }
 
 func (r *ruleSet) MustSeparator(s string, elemType reflect.Kind) RuleSet {
+	r.valid.own()
 	if r.setError != nil {
 		return r
 	}
 	if r.paramName == "" {
-		panic("unknown param name when set MustBool")
+		panic("unknown param name when set MustSeparator")
+	}
+	switch elemType {
+	case reflect.Int, reflect.Int64, reflect.Float64, reflect.Bool, reflect.String:
+	default:
+		return r.fail(fmt.Errorf("MustSeparator does not support element type %s", elemType))
 	}
 	r.valid.splitChar = s
+	r.valid.separatorMap[r.paramName] = s
 	r.valid.typeMap[r.paramName] = reflect.Slice
 	r.valid.elemTypeMap[r.paramName] = elemType
-	return r
+	//之前声明了MustType时元素使用该类型
+	if t, ok := r.valid.customTypeMap[r.paramName]; ok {
+		r.valid.elemTypeMap[r.paramName] = reflect.Interface
+		r.valid.ApiParams[r.paramName].Type = "[]" + t.name
+	}
+	return r.typeChanged()
+}
+
+//列表参数的分隔符
+func (v *Validator) separator(name string) string {
+	if s, ok := v.separatorMap[name]; ok {
+		return s
+	}
+	return v.splitChar
 }
 
 func (r *ruleSet) MustLength(length int) RuleSet {
@@ -504,10 +755,11 @@ func (r *ruleSet) MustLength(length int) RuleSet {
 		panic("unknown param name when set MustLength")
 	}
 	rl := new(rule)
+	rl.name = "MustLength"
 	rl.f = mustLength
+	rl.doc = fmt.Sprintf("长度=%d", length)
 	rl.args = append(rl.args, length)
-	r.valid.ApiParams[r.paramName].Rules = append(r.valid.ApiParams[r.paramName].Rules, *rl)
-	r.valid.ruleMap[r.paramName] = append(r.valid.ruleMap[r.paramName], *rl)
+	r.addRule(rl)
 	return r
 }
 
@@ -519,10 +771,11 @@ func (r *ruleSet) MustMin(min int) RuleSet {
 		panic("unknown param name when set MustMin")
 	}
 	rl := new(rule)
+	rl.name = "MustMin"
 	rl.f = mustMin
+	rl.doc = fmt.Sprintf(">=%d", min)
 	rl.args = append(rl.args, min)
-	r.valid.ApiParams[r.paramName].Rules = append(r.valid.ApiParams[r.paramName].Rules, *rl)
-	r.valid.ruleMap[r.paramName] = append(r.valid.ruleMap[r.paramName], *rl)
+	r.addRule(rl)
 	return r
 }
 
@@ -534,14 +787,88 @@ func (r *ruleSet) MustMax(max int) RuleSet {
 		panic("unknown param name when set MustMax")
 	}
 	rl := new(rule)
+	rl.name = "MustMax"
 	rl.f = mustMax
+	rl.doc = fmt.Sprintf("<=%d", max)
 	rl.args = append(rl.args, max)
+	r.addRule(rl)
+	return r
+}
 
-	r.valid.ApiParams[r.paramName].Rules = append(r.valid.ApiParams[r.paramName].Rules, *rl)
-	r.valid.ruleMap[r.paramName] = append(r.valid.ruleMap[r.paramName], *rl)
+//大于等于bound，bound可以是数值或time.Time，time.Time按参数的MustTimeLayout解析，与声明顺序无关
+func (r *ruleSet) MustMinValue(bound interface{}) RuleSet {
+	return r.boundRule("MustMinValue", mustMinValue, bound)
+}
+
+//小于等于bound
+func (r *ruleSet) MustMaxValue(bound interface{}) RuleSet {
+	return r.boundRule("MustMaxValue", mustMaxValue, bound)
+}
+
+//大于bound
+func (r *ruleSet) MustGreaterThanValue(bound interface{}) RuleSet {
+	return r.boundRule("MustGreaterThanValue", mustGreaterThanValue, bound)
+}
+
+//小于bound
+func (r *ruleSet) MustLessThanValue(bound interface{}) RuleSet {
+	return r.boundRule("MustLessThanValue", mustLessThanValue, bound)
+}
+
+var boundOps = map[string]string{
+	"MustMinValue":         ">=",
+	"MustMaxValue":         "<=",
+	"MustGreaterThanValue": ">",
+	"MustLessThanValue":    "<",
+}
+
+func (r *ruleSet) boundRule(name string, f ValidationFunc, bound interface{}) RuleSet {
+	if r.setError != nil {
+		return r
+	}
+	if r.paramName == "" {
+		panic("unknown param name when set " + name)
+	}
+	_, isTime := bound.(time.Time)
+	if _, isNum := toFloat64(bound); !isTime && !isNum {
+		return r.fail(fmt.Errorf("%s bound %v(%T) is not a number or time.Time", name, bound, bound))
+	}
+	rl := new(rule)
+	rl.name = name
+	rl.f = f
+	rl.args = append(rl.args, bound)
+	rl.doc = boundDoc(name, bound, r.valid.timeLayoutMap[r.paramName])
+	r.addRule(rl)
 	return r
 }
 
+func boundDoc(name string, bound interface{}, layout string) string {
+	if t, ok := bound.(time.Time); ok {
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		return boundOps[name] + t.Format(layout)
+	}
+	return fmt.Sprintf("%s%v", boundOps[name], bound)
+}
+
+//时间边界规则
+func timeBound(rl rule) (time.Time, bool) {
+	if _, ok := boundOps[rl.name]; !ok {
+		return time.Time{}, false
+	}
+	t, ok := rl.args[0].(time.Time)
+	return t, ok
+}
+
+//规则执行时的参数，时间边界追加参数当前的layout
+func (v *Validator) ruleArgs(name string, rl rule) []interface{} {
+	if t, ok := timeBound(rl); ok {
+		return []interface{}{t, v.timeLayoutMap[name]}
+	}
+	return rl.args
+}
+
 func (r *ruleSet) MustLengthRange(min, max int) RuleSet {
 	if r.setError != nil {
 		return r
@@ -550,44 +877,122 @@ func (r *ruleSet) MustLengthRange(min, max int) RuleSet {
 		panic("unknown param name when set MustLengthRange")
 	}
 	rl := new(rule)
+	rl.name = "MustLengthRange"
 	rl.f = mustLengthRange
+	rl.doc = fmt.Sprintf("长度[%d,%d]", min, max)
 	rl.args = append(rl.args, min)
 	rl.args = append(rl.args, max)
-
-	r.valid.ApiParams[r.paramName].Rules = append(r.valid.ApiParams[r.paramName].Rules, *rl)
-	r.valid.ruleMap[r.paramName] = append(r.valid.ruleMap[r.paramName], *rl)
+	r.addRule(rl)
 	return r
 }
 
+//values会转换为参数的类型，与类型规则的声明顺序无关，无法转换的值由Compile报告
 func (r *ruleSet) MustValues(values []interface{}) RuleSet {
 	if r.setError != nil {
 		return r
 	}
 	if r.paramName == "" {
-		panic("unknown param name when set MustLengthRange")
+		panic("unknown param name when set MustValues")
 	}
 	rl := new(rule)
+	rl.name = "MustValues"
 	rl.f = mustValues
+	rl.values = values
+	r.valid.convertValues(r.paramName, rl)
+	r.addRule(rl)
+	return r
+}
+
+//忽略大小写的字符串枚举
+func (r *ruleSet) MustValuesIgnoreCase(values []string) RuleSet {
+	if r.setError != nil {
+		return r
+	}
+	if r.paramName == "" {
+		panic("unknown param name when set MustValuesIgnoreCase")
+	}
+	rl := new(rule)
+	rl.name = "MustValuesIgnoreCase"
+	rl.f = mustValuesIgnoreCase
 	rl.args = append(rl.args, values)
+	rl.doc = fmt.Sprintf("可选值(忽略大小写)%v", values)
+	r.addRule(rl)
+	return r
+}
 
-	r.valid.ApiParams[r.paramName].Rules = append(r.valid.ApiParams[r.paramName].Rules, *rl)
-	r.valid.ruleMap[r.paramName] = append(r.valid.ruleMap[r.paramName], *rl)
+func (r *ruleSet) MustNotValues(values []interface{}) RuleSet {
+	if r.setError != nil {
+		return r
+	}
+	if r.paramName == "" {
+		panic("unknown param name when set MustNotValues")
+	}
+	rl := new(rule)
+	rl.name = "MustNotValues"
+	rl.f = mustNotValues
+	rl.values = values
+	r.valid.convertValues(r.paramName, rl)
+	r.addRule(rl)
 	return r
 }
 
+//使用Go枚举类型的取值作为可选值，实现fmt.Stringer的枚举值在字符串参数中使用String()
+func (r *ruleSet) MustEnum(enum EnumValues) RuleSet {
+	return r.MustValues(enum.EnumValues())
+}
+
+var valuesDocs = map[string]string{
+	"MustValues":    "可选值",
+	"MustNotValues": "不可选值",
+}
+
+//按参数当前的类型转换声明的可选值，无法转换的值保留原值
+func (v *Validator) convertValues(name string, rl *rule) {
+	kind := v.valueKind(name)
+	converted := make([]interface{}, 0, len(rl.values))
+	for _, value := range rl.values {
+		if cv, err := v.convertParamValue(name, value, kind); err == nil {
+			value = cv
+		}
+		converted = append(converted, value)
+	}
+	rl.args = []interface{}{converted}
+	rl.doc = fmt.Sprintf("%s%v", valuesDocs[rl.name], v.displayValue(name, converted))
+}
+
+//参数类型变化后重新转换可选值和默认值
+func (r *ruleSet) typeChanged() RuleSet {
+	r.updateRules(func(rl *rule) {
+		if _, ok := valuesDocs[rl.name]; ok {
+			r.valid.convertValues(r.paramName, rl)
+		}
+	})
+	return r.coerceDefault()
+}
+
 func (r *ruleSet) MustTimeLayout(layout string) RuleSet {
+	r.valid.own()
 	if r.setError != nil {
 		return r
 	}
 	if r.paramName == "" {
-		panic("unknown param name when set MustLengthRange")
+		panic("unknown param name when set MustTimeLayout")
+	}
+	if err := checkTimeLayout(layout); err != nil {
+		return r.fail(err)
 	}
+	r.valid.timeLayoutMap[r.paramName] = layout
+	r.updateRules(func(rl *rule) {
+		if t, ok := timeBound(*rl); ok {
+			rl.doc = boundDoc(rl.name, t, layout)
+		}
+	})
 	rl := new(rule)
+	rl.name = "MustTimeLayout"
 	rl.f = mustTimeLayout
+	rl.doc = "格式" + layout
 	rl.args = append(rl.args, layout)
-
-	r.valid.ApiParams[r.paramName].Rules = append(r.valid.ApiParams[r.paramName].Rules, *rl)
-	r.valid.ruleMap[r.paramName] = append(r.valid.ruleMap[r.paramName], *rl)
+	r.addRule(rl)
 	return r
 }
 
@@ -599,11 +1004,12 @@ func (r *ruleSet) MustLessThan(field string) RuleSet {
 		panic("unknown param name when set MustLessThan")
 	}
 	rl := new(rule)
+	rl.name = "MustLessThan"
 	rl.f = mustLessThan
+	rl.fieldArg = true
+	rl.doc = "<参数" + field
 	rl.args = append(rl.args, field)
-
-	r.valid.ApiParams[r.paramName].Rules = append(r.valid.ApiParams[r.paramName].Rules, *rl)
-	r.valid.ruleMap[r.paramName] = append(r.valid.ruleMap[r.paramName], *rl)
+	r.addRule(rl)
 	return r
 }
 
@@ -615,14 +1021,70 @@ func (r *ruleSet) MustLargeThan(field string) RuleSet {
 		panic("unknown param name when set MustLessThan")
 	}
 	rl := new(rule)
+	rl.name = "MustLargeThan"
 	rl.f = mustLargeThan
+	rl.fieldArg = true
+	rl.doc = ">参数" + field
 	rl.args = append(rl.args, field)
-
-	r.valid.ApiParams[r.paramName].Rules = append(r.valid.ApiParams[r.paramName].Rules, *rl)
-	r.valid.ruleMap[r.paramName] = append(r.valid.ruleMap[r.paramName], *rl)
+	r.addRule(rl)
 	return r
 }
 
+//正则在声明时编译，无效的正则会panic
+func (r *ruleSet) MustMatch(pattern string) RuleSet {
+	re := compilePattern("MustMatch", r.paramName, pattern)
+	return r.appendRule("MustMatch", mustMatch, "匹配/"+pattern+"/", re)
+}
+
+func (r *ruleSet) MustNotMatch(pattern string) RuleSet {
+	re := compilePattern("MustNotMatch", r.paramName, pattern)
+	return r.appendRule("MustNotMatch", mustNotMatch, "不匹配/"+pattern+"/", re)
+}
+
+func (r *ruleSet) MustPrefix(prefix string) RuleSet {
+	return r.appendRule("MustPrefix", mustPrefix, "前缀"+prefix, prefix)
+}
+
+func (r *ruleSet) MustSuffix(suffix string) RuleSet {
+	return r.appendRule("MustSuffix", mustSuffix, "后缀"+suffix, suffix)
+}
+
+func (r *ruleSet) MustContains(sub string) RuleSet {
+	return r.appendRule("MustContains", mustContains, "包含"+sub, sub)
+}
+
+func (r *ruleSet) MustASCII() RuleSet {
+	return r.appendRule("MustASCII", mustCharClass, "ASCII字符", asciiClass)
+}
+
+func (r *ruleSet) MustAlphanumeric() RuleSet {
+	return r.appendRule("MustAlphanumeric", mustCharClass, "字母和数字", alphanumericClass)
+}
+
+func (r *ruleSet) MustDigits() RuleSet {
+	return r.appendRule("MustDigits", mustCharClass, "数字", digitsClass)
+}
+
+func (r *ruleSet) MustHan() RuleSet {
+	return r.appendRule("MustHan", mustCharClass, "汉字", hanClass)
+}
+
+func (r *ruleSet) MustNoEmoji() RuleSet {
+	return r.appendRule("MustNoEmoji", mustCharClass, "无表情符号", noEmojiClass)
+}
+
+func (r *ruleSet) MustNoControl() RuleSet {
+	return r.appendRule("MustNoControl", mustCharClass, "无控制字符", noControlClass)
+}
+
+func compilePattern(name, paramName, pattern string) *regexp.Regexp {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		panic(fmt.Sprintf("%s of param %s: %s", name, paramName, err.Error()))
+	}
+	return re
+}
+
 func (r *ruleSet) MustFunc(f ValidationFunc, args []interface{}) RuleSet {
 	if r.setError != nil {
 		return r
@@ -631,10 +1093,50 @@ func (r *ruleSet) MustFunc(f ValidationFunc, args []interface{}) RuleSet {
 		panic("unknown param name when set MustLengthRange")
 	}
 	rl := new(rule)
-	rl.f = f
+	rl.name = "MustFunc"
+	rl.f = withDefaultCode(f, CodeMustFunc)
 	rl.args = args
+	r.addRule(rl)
+	return r
+}
 
+func (r *ruleSet) appendRule(name string, f ValidationFunc, doc string, args ...interface{}) RuleSet {
+	if r.setError != nil {
+		return r
+	}
+	if r.paramName == "" {
+		panic("unknown param name when set " + name)
+	}
+	r.addRule(&rule{f: f, args: args, doc: doc, name: name})
+	return r
+}
+
+func (r *ruleSet) addRule(rl *rule) {
+	r.valid.own()
 	r.valid.ApiParams[r.paramName].Rules = append(r.valid.ApiParams[r.paramName].Rules, *rl)
 	r.valid.ruleMap[r.paramName] = append(r.valid.ruleMap[r.paramName], *rl)
-	return r
+}
+
+//修改参数已声明的规则，文档和校验使用的规则同时修改
+func (r *ruleSet) updateRules(f func(*rule)) {
+	r.valid.own()
+	rules := r.valid.ApiParams[r.paramName].Rules
+	for i := range rules {
+		f(&rules[i])
+	}
+	rules = r.valid.ruleMap[r.paramName]
+	for i := range rules {
+		f(&rules[i])
+	}
+}
+
+//文档中展示的规则说明
+func (p *Params) RuleDoc() string {
+	var docs []string
+	for _, rl := range p.Rules {
+		if rl.doc != "" {
+			docs = append(docs, rl.doc)
+		}
+	}
+	return strings.Join(docs, ", ")
 }
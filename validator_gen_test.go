@@ -0,0 +1,242 @@
+// Code generated by validgen. DO NOT EDIT.
+
+package validator
+
+import "reflect"
+
+// 根据GenRequest的标签声明参数的Validator
+func NewGenRequestValidator() *Validator {
+	v := NewValidator()
+	v.NewParam("page", "1").MustInt().MustMin(1)
+	v.NewParam("size", "20").MustInt().MustMax(100)
+	v.NewParam("keyword").Trim().Label("关键字")
+	v.NewParam("level").MustInt()
+	v.NewParam("ids").MustSeparator(",", reflect.Int64)
+	v.NewParam("tags").MustSeparator(",", reflect.String)
+	v.NewParam("ratio").MustFloat64()
+	v.NewParam("active").MustBool()
+	v.NewParam("site").MustURL()
+	v.NewParam("email").MustEmail()
+	v.NewParam("node")
+	v.NewParam("target")
+	return v
+}
+
+// 不使用反射的参数绑定，结果与ValuesToStruct一致
+func (s *GenRequest) BindFrom(v *Validator) error {
+	_, err := s.bindFrom(v)
+	return err
+}
+
+func (s *GenRequest) bindFrom(v *Validator) (bool, error) {
+	bound := false
+	{
+		p := s.GenPaging
+		if p == nil {
+			p = new(GenPaging)
+		}
+		ok, err := func(s *GenPaging) (bool, error) {
+			bound := false
+			if ok, err := BindPtr(v, "page", "GenPaging.Page", &s.Page, SetInt); err != nil {
+				return bound, err
+			} else if ok {
+				bound = true
+			}
+			if ok, err := Bind(v, "size", "GenPaging.Size", &s.Size, SetUint); err != nil {
+				return bound, err
+			} else if ok {
+				bound = true
+			}
+			return bound, nil
+		}(p)
+		if err != nil {
+			return bound, err
+		}
+		if ok {
+			s.GenPaging = p
+			bound = true
+		}
+	}
+	if ok, err := Bind(v, "keyword", "Keyword", &s.Keyword, SetString); err != nil {
+		return bound, err
+	} else if ok {
+		bound = true
+	}
+	if ok, err := Bind(v, "level", "Level", &s.Level, SetInt); err != nil {
+		return bound, err
+	} else if ok {
+		bound = true
+	}
+	if ok, err := BindSlice(v, "ids", "IDs", &s.IDs, SetInt); err != nil {
+		return bound, err
+	} else if ok {
+		bound = true
+	}
+	if ok, err := BindPtrSlice(v, "tags", "Tags", &s.Tags, SetString); err != nil {
+		return bound, err
+	} else if ok {
+		bound = true
+	}
+	if ok, err := Bind(v, "ratio", "Ratio", &s.Ratio, SetFloat); err != nil {
+		return bound, err
+	} else if ok {
+		bound = true
+	}
+	if ok, err := BindPtr(v, "active", "Active", &s.Active, SetBool); err != nil {
+		return bound, err
+	} else if ok {
+		bound = true
+	}
+	if ok, err := BindPtr(v, "site", "Site", &s.Site, SetAny); err != nil {
+		return bound, err
+	} else if ok {
+		bound = true
+	}
+	if ok, err := Bind(v, "email", "Filter.Email", &s.Filter.Email, SetString); err != nil {
+		return bound, err
+	} else if ok {
+		bound = true
+	}
+	{
+		p := s.Node
+		if p == nil {
+			p = new(GenNode)
+		}
+		ok, err := func(s *GenNode) (bool, error) {
+			bound := false
+			if ok, err := Bind(v, "node", "Node.Label", &s.Label, SetString); err != nil {
+				return bound, err
+			} else if ok {
+				bound = true
+			}
+			{
+				p := s.Link
+				if p == nil {
+					p = new(GenLink)
+				}
+				ok, err := func(s *GenLink) (bool, error) {
+					bound := false
+					if ok, err := Bind(v, "target", "Node.Link.Target", &s.Target, SetString); err != nil {
+						return bound, err
+					} else if ok {
+						bound = true
+					}
+					return bound, nil
+				}(p)
+				if err != nil {
+					return bound, err
+				}
+				if ok {
+					s.Link = p
+					bound = true
+				}
+			}
+			return bound, nil
+		}(p)
+		if err != nil {
+			return bound, err
+		}
+		if ok {
+			s.Node = p
+			bound = true
+		}
+	}
+	return bound, nil
+}
+
+// 不使用反射的参数绑定，结果与ValuesToStruct一致
+func (s *GenPaging) BindFrom(v *Validator) error {
+	_, err := s.bindFrom(v)
+	return err
+}
+
+func (s *GenPaging) bindFrom(v *Validator) (bool, error) {
+	bound := false
+	if ok, err := BindPtr(v, "page", "Page", &s.Page, SetInt); err != nil {
+		return bound, err
+	} else if ok {
+		bound = true
+	}
+	if ok, err := Bind(v, "size", "Size", &s.Size, SetUint); err != nil {
+		return bound, err
+	} else if ok {
+		bound = true
+	}
+	return bound, nil
+}
+
+// 不使用反射的参数绑定，结果与ValuesToStruct一致
+func (s *GenNode) BindFrom(v *Validator) error {
+	_, err := s.bindFrom(v)
+	return err
+}
+
+func (s *GenNode) bindFrom(v *Validator) (bool, error) {
+	bound := false
+	if ok, err := Bind(v, "node", "Label", &s.Label, SetString); err != nil {
+		return bound, err
+	} else if ok {
+		bound = true
+	}
+	{
+		p := s.Link
+		if p == nil {
+			p = new(GenLink)
+		}
+		ok, err := func(s *GenLink) (bool, error) {
+			bound := false
+			if ok, err := Bind(v, "target", "Link.Target", &s.Target, SetString); err != nil {
+				return bound, err
+			} else if ok {
+				bound = true
+			}
+			return bound, nil
+		}(p)
+		if err != nil {
+			return bound, err
+		}
+		if ok {
+			s.Link = p
+			bound = true
+		}
+	}
+	return bound, nil
+}
+
+// 不使用反射的参数绑定，结果与ValuesToStruct一致
+func (s *GenLink) BindFrom(v *Validator) error {
+	_, err := s.bindFrom(v)
+	return err
+}
+
+func (s *GenLink) bindFrom(v *Validator) (bool, error) {
+	bound := false
+	if ok, err := Bind(v, "target", "Target", &s.Target, SetString); err != nil {
+		return bound, err
+	} else if ok {
+		bound = true
+	}
+	{
+		p := s.Back
+		if p == nil {
+			p = new(GenNode)
+		}
+		ok, err := func(s *GenNode) (bool, error) {
+			bound := false
+			if ok, err := Bind(v, "node", "Back.Label", &s.Label, SetString); err != nil {
+				return bound, err
+			} else if ok {
+				bound = true
+			}
+			return bound, nil
+		}(p)
+		if err != nil {
+			return bound, err
+		}
+		if ok {
+			s.Back = p
+			bound = true
+		}
+	}
+	return bound, nil
+}
@@ -1,10 +1,23 @@
 package validator
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"testing"
 	. "github.com/smartystreets/goconvey/convey"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http/httptest"
 	"net/url"
-	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-wyvern/validator/internal/gen"
 )
 
 func Test_Validate(t *testing.T) {
@@ -18,10 +31,1237 @@ func Test_Validate(t *testing.T) {
 			2,
 			3,
 		})
-		err:=Validator(params,defaultValidatorRules)
-		if err!=nil{
-			fmt.Println(err.Error())
+		err:=Validate(params,defaultValidatorRules)
+		So(err, ShouldBeNil)
+	})
+}
+
+func Test_ErrorCode(t *testing.T) {
+	Convey("测试错误码及JSON序列化", t, func() {
+		v := NewValidator()
+		v.NewParam("page").MustInt().MustMin(1)
+		v.NewParam("name").MustFunc(func(key string, value interface{}, params url.Values, cus bool, args ...interface{}) error {
+			return NewParamsError(key, value).CustomErrorText("name错误")
+		}, nil)
+
+		err := Validate(url.Values{"page": {"0"}}, v)
+		So(errors.Is(err, CodeMustMin), ShouldBeTrue)
+		So(errors.Is(err, CodeMustMax), ShouldBeFalse)
+		body, _ := json.Marshal(err)
+		So(string(body), ShouldEqual, `{"code":"must_min","param":"page","label":"page","args":[1],"msg":"参数[page]的值必须大于等于1"}`)
+
+		So(errors.Is(Validate(url.Values{"page": {"a"}}, v), CodeMustInt), ShouldBeTrue)
+		So(errors.Is(Validate(url.Values{"name": {"x"}}, v), CodeMustFunc), ShouldBeTrue)
+
+		errs := ParamsErrors{NewParamsError("a", nil).ErrRequireParam(false), NewParamsError("b", nil).ErrUnknownParam(false)}
+		So(errors.Is(errs, CodeUnknownParam), ShouldBeTrue)
+		So(errs.Error(), ShouldEqual, "a是必须的参数; 未知的参数:b")
+		So(AsParamsErrors(errs), ShouldHaveLength, 2)
+		So(AsParamsErrors(nil), ShouldBeNil)
+
+		v.SetCustomError()
+		err = Validate(url.Values{"page": {"0"}}, v)
+		So(err.Error(), ShouldEqual, CustomMustMinTpl)
+		So(errors.Is(err, CodeMustMin), ShouldBeTrue)
+	})
+}
+
+func Test_ErrorRenderer(t *testing.T) {
+	Convey("测试错误渲染", t, func() {
+		pErr := NewParamsError("page", 0)
+		pErr.Args = []interface{}{1}
+		pErr.ErrMustMin(false)
+
+		status, contentType, body := GetErrorRenderer(ProblemRendererName).Render(pErr)
+		So(status, ShouldEqual, 400)
+		So(contentType, ShouldEqual, ProblemContentType)
+		So(string(body), ShouldContainSubstring, `"invalid-params":[{"name":"page","reason":"参数[page]的值必须大于等于1","code":"must_min","args":[1]}]`)
+
+		status, _, body = GetErrorRenderer(ProblemRendererName).Render(context.Canceled)
+		So(status, ShouldEqual, 500)
+		So(string(body), ShouldNotContainSubstring, "invalid-params")
+		So(string(body), ShouldContainSubstring, `"detail":"服务器内部错误"`)
+
+		status, _, body = GetErrorRenderer(SimpleRendererName).Render(pErr)
+		So(status, ShouldEqual, 400)
+		So(string(body), ShouldContainSubstring, `"code":"must_min"`)
+
+		status, _, body = GetErrorRenderer(SimpleRendererName).Render(context.DeadlineExceeded)
+		So(status, ShouldEqual, 500)
+		So(string(body), ShouldEqual, `{"code":"","msg":"服务器内部错误","fields":[]}`)
+	})
+
+	Convey("测试Api的错误返回示例和错误响应", t, func() {
+		v := NewValidator()
+		api := NewApi("GET", "/items", "列表", nil, v)
+		v.NewParam("page").Require(true)
+		So(string(api.FailDoc()), ShouldContainSubstring, `"name": "page"`)
+
+		api.SetFailRenderer(SimpleRendererName)
+		So(string(api.FailDoc()), ShouldContainSubstring, `"code": "require_param"`)
+		w := httptest.NewRecorder()
+		So(api.WriteError(w, Validate(url.Values{}, v)), ShouldBeNil)
+		So(w.Code, ShouldEqual, 400)
+		So(w.Header().Get("Content-Type"), ShouldEqual, JsonContentType)
+
+		api.StdFormat = "json"
+		api.SetFailStdOut(map[string]string{"msg": "fail"})
+		So(string(api.FailDoc()), ShouldContainSubstring, `"msg": "fail"`)
+	})
+}
+
+func Test_ErrorText(t *testing.T) {
+	Convey("测试错误信息渲染", t, func() {
+		v := NewValidator()
+		v.NewParam("q").MustValues([]interface{}{"<a&b>"}).Label(`"查询"`)
+		err := Validate(url.Values{"q": {"x"}}, v)
+		So(err.Error(), ShouldEqual, `参数["查询"]的值必须在[<a&b>]的范围中`)
+
+		pErr := NewParamsError("q", "{{.Key}}").ErrMustMin(false).CustomErrorText("{{.Key}}无效")
+		So(pErr.SetLabel("查询").Error(), ShouldEqual, "{{.Key}}无效")
+		So(pErr.Render(), ShouldBeNil)
+		So(pErr.Error(), ShouldEqual, "q无效")
+
+		pErr = NewParamsError("q", "x").CustomErrorText("{{.Key无效")
+		So(pErr.Render(), ShouldNotBeNil)
+		So(pErr.Tr().Error(), ShouldEqual, "{{.Key无效")
+
+		v.NewParam("r").MustRule(RuleFunc(func(ctx context.Context, value interface{}, fields Fields) Outcome {
+			return Fail("", value.(string))
+		}))
+		So(Validate(url.Values{"r": {"{{.Label}}"}}, v).Error(), ShouldEqual, "{{.Label}}")
+	})
+}
+
+func Test_Label(t *testing.T) {
+	Convey("测试参数显示名", t, func() {
+		v := NewValidator()
+		v.NewParam("page").MustInt().MustMin(1).Label("页码").LocaleLabel("en", "Page")
+		v.NewParam("token").Require(true).Sensitive()
+		v.NewParam("size").MustInt()
+
+		err := Validate(url.Values{"page": {"0"}, "token": {"t"}}, v)
+		So(err.Error(), ShouldEqual, "参数[页码]的值必须大于等于1")
+		So(err.(*ParamsError).Label, ShouldEqual, "页码")
+		So(err.(*ParamsError).Key, ShouldEqual, "page")
+
+		v.SetLocale("en")
+		So(v.ParamLabel("page"), ShouldEqual, "Page")
+		So(v.ParamLabel("size"), ShouldEqual, "size")
+		So(Validate(url.Values{"page": {"0"}, "token": {"t"}}, v).Error(), ShouldEqual, "参数[Page]的值必须大于等于1")
+
+		v.NewParam("token").MustLength(3).Sensitive()
+		err = Validate(url.Values{"token": {"secret"}}, v)
+		So(err, ShouldNotBeNil)
+		So(err.(*ParamsError).Value, ShouldBeNil)
+	})
+}
+
+func Test_BoundRules(t *testing.T) {
+	Convey("测试边界规则", t, func() {
+		v := NewValidator()
+		v.NewParam("n").MustInt().MustGreaterThanValue(0).MustLessThanValue(10)
+		v.NewParam("f").MustFloat64().MustMinValue(0.5).MustMaxValue(1)
+		So(Validate(url.Values{"n": {"5"}, "f": {"1"}}, v), ShouldBeNil)
+		So(errors.Is(Validate(url.Values{"n": {"0"}}, v), CodeMustGreaterThanValue), ShouldBeTrue)
+		So(errors.Is(Validate(url.Values{"n": {"10"}}, v), CodeMustLessThanValue), ShouldBeTrue)
+		So(errors.Is(Validate(url.Values{"f": {"0.4"}}, v), CodeMustMin), ShouldBeTrue)
+		So(v.ApiParams["n"].RuleDoc(), ShouldEqual, ">0, <10")
+	})
+
+	Convey("测试时间边界与MustTimeLayout的声明顺序无关", t, func() {
+		bound := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+		for _, declare := range []func(RuleSet){
+			func(r RuleSet) { r.MustMinValue(bound).MustTimeLayout("2006-01-02") },
+			func(r RuleSet) { r.MustTimeLayout("2006-01-02").MustMinValue(bound) },
+		} {
+			v := NewValidator()
+			declare(v.NewParam("day"))
+			So(v.Compile(), ShouldBeNil)
+			So(v.ApiParams["day"].RuleDoc(), ShouldContainSubstring, ">=2030-01-01")
+			So(Validate(url.Values{"day": {"2031-05-01"}}, v), ShouldBeNil)
+			err := Validate(url.Values{"day": {"2000-01-01"}}, v)
+			So(errors.Is(err, CodeMustMin), ShouldBeTrue)
+			So(err.Error(), ShouldEqual, "参数[day]的值必须大于等于2030-01-01")
+		}
+	})
+
+	Convey("测试无法比较的边界", t, func() {
+		v := NewValidator()
+		v.NewParam("n").MustInt().MustMinValue("5")
+		So(v.Compile(), ShouldNotBeNil)
+
+		v = NewValidator()
+		v.NewParam("s").MustMinValue(5)
+		So(v.Compile(), ShouldNotBeNil)
+		So(Validate(url.Values{"s": {"9"}}, v), ShouldNotBeNil)
+
+		v = NewValidator()
+		v.NewParam("q").MustMin(3).MustMax(5)
+		So(v.Compile(), ShouldNotBeNil)
+		So(Validate(url.Values{"q": {"hello"}}, v), ShouldBeNil)
+	})
+}
+
+func Test_MustValues(t *testing.T) {
+	Convey("测试可选值与类型规则的声明顺序无关", t, func() {
+		for _, declare := range []func(RuleSet){
+			func(r RuleSet) { r.MustValues([]interface{}{1, "2"}).MustNotValues([]interface{}{"3"}).MustInt() },
+			func(r RuleSet) { r.MustInt().MustValues([]interface{}{1, "2"}).MustNotValues([]interface{}{"3"}) },
+		} {
+			v := NewValidator()
+			declare(v.NewParam("s"))
+			So(v.Compile(), ShouldBeNil)
+			So(v.ApiParams["s"].RuleDoc(), ShouldEqual, "可选值[1 2], 不可选值[3]")
+			So(Validate(url.Values{"s": {"1"}}, v), ShouldBeNil)
+			So(Validate(url.Values{"s": {"2"}}, v), ShouldBeNil)
+			So(errors.Is(Validate(url.Values{"s": {"4"}}, v), CodeMustValues), ShouldBeTrue)
+		}
+	})
+
+	Convey("测试可选值与参数类型不匹配", t, func() {
+		v := NewValidator()
+		v.NewParam("s").MustValues([]interface{}{"a"}).MustInt()
+		So(v.Compile(), ShouldNotBeNil)
+
+		v = NewValidator()
+		v.NewParam("ids").MustSeparator(",", reflect.Int).MustNotValues([]interface{}{0.5})
+		So(v.Compile(), ShouldNotBeNil)
+	})
+}
+
+func Test_StringRules(t *testing.T) {
+	Convey("测试正则及字符串规则", t, func() {
+		v := NewValidator()
+		v.NewParam("code").MustMatch(`^[A-Z]{2}\d+$`).MustNotMatch(`^XX`)
+		v.NewParam("path").MustPrefix("/api/").MustSuffix(".json").MustContains("v1")
+		v.NewParam("ascii").MustASCII()
+		v.NewParam("alnum").MustAlphanumeric()
+		v.NewParam("digits").MustDigits()
+		v.NewParam("han").MustHan()
+		v.NewParam("text").MustNoEmoji().MustNoControl()
+		So(v.Compile(), ShouldBeNil)
+
+		So(Validate(url.Values{"code": {"AB12"}, "path": {"/api/v1/a.json"}, "ascii": {"a-b"}, "alnum": {"a1"},
+			"digits": {"42"}, "han": {"中文"}, "text": {"你好 hi"}}, v), ShouldBeNil)
+
+		for _, c := range []struct {
+			params url.Values
+			code   ErrorCode
+		}{
+			{url.Values{"code": {"ab12"}}, CodeMustMatch},
+			{url.Values{"code": {"XX12"}}, CodeMustNotMatch},
+			{url.Values{"path": {"/v1/a.json"}}, CodeMustPrefix},
+			{url.Values{"path": {"/api/v1/a.xml"}}, CodeMustSuffix},
+			{url.Values{"path": {"/api/v2/a.json"}}, CodeMustContains},
+			{url.Values{"ascii": {"é"}}, CodeMustASCII},
+			{url.Values{"alnum": {"a_1"}}, CodeMustAlphanumeric},
+			{url.Values{"digits": {"4.2"}}, CodeMustDigits},
+			{url.Values{"han": {"中a"}}, CodeMustHan},
+			{url.Values{"text": {"hi \U0001F600"}}, CodeMustNoEmoji},
+			{url.Values{"text": {"a\u200bb"}}, CodeMustNoControl},
+		} {
+			So(errors.Is(Validate(c.params, v), c.code), ShouldBeTrue)
+		}
+
+		err := Validate(url.Values{"code": {"ab"}}, v)
+		So(err.Error(), ShouldEqual, `参数[code]的格式不正确,必须匹配^[A-Z]{2}\d+$`)
+		So(func() { NewValidator().NewParam("bad").MustMatch("(") }, ShouldPanic)
+
+		v.NewParam("n").MustInt().MustPrefix("1")
+		So(v.Compile(), ShouldNotBeNil)
+	})
+}
+
+func Test_Format(t *testing.T) {
+	Convey("测试格式校验", t, func() {
+		v := NewValidator()
+		v.NewParam("email").MustEmail()
+		v.NewParam("site").MustURL()
+		v.NewParam("ip").MustIPv4()
+		v.NewParam("id").MustUUID()
+		So(v.Compile(), ShouldBeNil)
+		So(Validate(url.Values{"email": {"a@b.cn"}, "site": {"https://example.com/x"}, "ip": {"10.0.0.1"},
+			"id": {"123e4567-e89b-12d3-a456-426614174000"}}, v), ShouldBeNil)
+		So(errors.Is(Validate(url.Values{"email": {"a@"}}, v), CodeMustEmail), ShouldBeTrue)
+		So(errors.Is(Validate(url.Values{"ip": {"::1"}}, v), CodeMustIPv4), ShouldBeTrue)
+
+		So(Validate(url.Values{"site": {"https://example.com/x"}}, v), ShouldBeNil)
+		var dst struct {
+			Site *url.URL `valid:"site"`
+		}
+		So(v.ValuesToStruct(&dst), ShouldBeNil)
+		So(dst.Site.Host, ShouldEqual, "example.com")
+	})
+
+	Convey("测试同一参数声明多种格式", t, func() {
+		v := NewValidator()
+		v.NewParam("host").MustEmail().MustHostname()
+		So(v.Compile(), ShouldNotBeNil)
+		So(errors.Is(Validate(url.Values{"host": {"example.com"}}, v), CodeMustEmail), ShouldBeTrue)
+
+		v = NewValidator()
+		v.NewParam("email").MustEmail().MustEmail()
+		So(v.Compile(), ShouldBeNil)
+	})
+}
+
+func Test_ChinaFormats(t *testing.T) {
+	Convey("测试身份证号解析", t, func() {
+		info, err := ParseIDCardCN("11010519491231002x")
+		So(err, ShouldBeNil)
+		So(info.Region, ShouldEqual, "110105")
+		So(info.Birthday.Format("20060102"), ShouldEqual, "19491231")
+		So(info.Gender, ShouldEqual, GenderFemale)
+
+		info, err = ParseIDCardCN("440308199001010017")
+		So(err, ShouldBeNil)
+		So(info.Gender, ShouldEqual, GenderMale)
+
+		for _, s := range []string{
+			"110105194912310021",  //校验码错误
+			"110105194913310013",  //月份不存在
+			"110105209912310010",  //出生日期晚于今天
+			"1101051949123100",    //位数不足
+			"11010519491231002XX", //位数过多
+			"1101A519491231002X",  //前17位含字母
+		} {
+			_, err := ParseIDCardCN(s)
+			So(err, ShouldNotBeNil)
 		}
-		//So(err,ShouldBeNil())
+	})
+
+	Convey("测试统一社会信用代码及银行卡号", t, func() {
+		So(isCreditCodeCN("91350100M000100Y43"), ShouldBeTrue)
+		So(isCreditCodeCN("91110000600037341L"), ShouldBeTrue)
+		So(isCreditCodeCN("91350100M000100Y44"), ShouldBeFalse)
+		So(isCreditCodeCN("91350100I000100Y43"), ShouldBeFalse)
+		So(isCreditCodeCN("91350100M000100Y4"), ShouldBeFalse)
+
+		So(luhn("4111111111111111"), ShouldBeTrue)
+		So(luhn("79927398713"), ShouldBeTrue)
+		So(luhn("4111111111111112"), ShouldBeFalse)
+		So(luhn("4111-1111-1111-1111"), ShouldBeFalse)
+	})
+
+	Convey("测试中国大陆格式规则", t, func() {
+		v := NewValidator()
+		v.NewParam("mobile").MustMobileCN()
+		v.NewParam("id_card").MustIDCardCN()
+		v.NewParam("credit_code").MustCreditCodeCN()
+		v.NewParam("postcode").MustPostcodeCN()
+		v.NewParam("card").MustBankCard()
+		So(v.Compile(), ShouldBeNil)
+		So(v.paramSchema("mobile")["format"], ShouldEqual, "mobile_cn")
+		So(v.ApiParams["id_card"].RuleDoc(), ShouldEqual, "格式身份证号")
+		So(Validate(url.Values{"mobile": {"+8613800138000"}, "id_card": {"11010519491231002X"},
+			"credit_code": {"91350100M000100Y43"}, "postcode": {"100101"}, "card": {"4111111111111111"}}, v), ShouldBeNil)
+
+		for _, c := range []struct {
+			params url.Values
+			code   ErrorCode
+		}{
+			{url.Values{"mobile": {"12800138000"}}, CodeMustMobileCN},
+			{url.Values{"mobile": {"1380013800"}}, CodeMustMobileCN},
+			{url.Values{"id_card": {"110105194912310021"}}, CodeMustIDCardCN},
+			{url.Values{"credit_code": {"91350100M000100Y44"}}, CodeMustCreditCodeCN},
+			{url.Values{"postcode": {"900101"}}, CodeMustPostcodeCN},
+			{url.Values{"card": {"4111111111111112"}}, CodeMustBankCard},
+			{url.Values{"card": {"79927398713"}}, CodeMustBankCard},
+		} {
+			So(errors.Is(Validate(c.params, v), c.code), ShouldBeTrue)
+		}
+	})
+}
+
+func Test_PasswordPolicy(t *testing.T) {
+	Convey("测试密码策略各检查项", t, func() {
+		So((&PasswordPolicy{}).Check("a"), ShouldBeEmpty)
+		for _, c := range []struct {
+			policy   PasswordPolicy
+			password string
+			failed   []string
+		}{
+			{PasswordPolicy{MinLength: 8}, "Ab1!xyz", []string{PasswordMinLength}},
+			{PasswordPolicy{MinLength: 3}, "密码长", nil},
+			{PasswordPolicy{RequireLower: true}, "ABC123", []string{PasswordRequireLower}},
+			{PasswordPolicy{RequireUpper: true}, "abc123", []string{PasswordRequireUpper}},
+			{PasswordPolicy{RequireDigit: true}, "abcDEF", []string{PasswordRequireDigit}},
+			{PasswordPolicy{RequireSymbol: true}, "abc123", []string{PasswordRequireSymbol}},
+			{PasswordPolicy{RequireSymbol: true}, "abc+123", nil},
+			{PasswordPolicy{MaxRepeat: 2}, "abbbc", []string{PasswordMaxRepeat}},
+			{PasswordPolicy{MaxRepeat: 2}, "abbcc", nil},
+			{PasswordPolicy{ForbiddenSequences: []string{"qwerty", "123456"}}, "xQWERTYx", []string{PasswordForbiddenSeq}},
+			{PasswordPolicy{MinLength: 10, RequireUpper: true, RequireSymbol: true}, "abc123", []string{PasswordMinLength, PasswordRequireUpper, PasswordRequireSymbol}},
+		} {
+			So(c.policy.Check(c.password), ShouldResemble, c.failed)
+		}
+	})
+
+	Convey("测试密码黑名单", t, func() {
+		filename := t.TempDir() + "/blocklist.txt"
+		So(os.WriteFile(filename, []byte("# common\nPassword1\n\n  iloveyou  \n"), 0644), ShouldBeNil)
+		policy := &PasswordPolicy{}
+		So(policy.LoadBlocklist(filename), ShouldBeNil)
+		So(policy.Check("password1"), ShouldResemble, []string{PasswordBlocklist})
+		So(policy.Check("ILOVEYOU"), ShouldResemble, []string{PasswordBlocklist})
+		So(policy.Check("# common"), ShouldBeEmpty)
+		So(policy.Check("password12"), ShouldBeEmpty)
+		So(policy.LoadBlocklist(filename+".missing"), ShouldNotBeNil)
+	})
+
+	Convey("测试密码策略规则", t, func() {
+		v := NewValidator()
+		v.NewParam("password").Require(true).MustPasswordPolicy(&PasswordPolicy{MinLength: 8, RequireDigit: true})
+		So(v.Compile(), ShouldBeNil)
+		So(v.ApiParams["password"].Sensitive, ShouldBeTrue)
+		So(Validate(url.Values{"password": {"abcdefg1"}}, v), ShouldBeNil)
+
+		err := Validate(url.Values{"password": {"abc"}}, v)
+		So(errors.Is(err, CodeMustPasswordPolicy), ShouldBeTrue)
+		So(err.(*ParamsError).Args, ShouldResemble, []interface{}{PasswordMinLength, PasswordRequireDigit})
+		So(err.(*ParamsError).Value, ShouldBeNil)
+		So(err.Error(), ShouldEqual, "参数[password]强度不足,未满足:min_length,require_digit")
+		So(err.Error(), ShouldNotContainSubstring, "abc")
+	})
+}
+
+func Test_WordDict(t *testing.T) {
+	Convey("测试敏感词匹配", t, func() {
+		d := NewWordDict([]string{"he", "she", "his", "hers", "she", ""}, WordDictOptions{})
+		So(d.Len(), ShouldEqual, 4)
+
+		word, pos, ok := d.Find("ushers")
+		So(ok, ShouldBeTrue)
+		So(word, ShouldEqual, "she")
+		So(pos, ShouldEqual, 1)
+
+		word, pos, ok = d.Find("ahishe")
+		So(ok, ShouldBeTrue)
+		So(word, ShouldEqual, "his")
+		So(pos, ShouldEqual, 1)
+
+		_, _, ok = d.Find("sh-e")
+		So(ok, ShouldBeFalse)
+	})
+
+	Convey("测试一个词是另一个词中间的一段", t, func() {
+		d := NewWordDict([]string{"abcde", "cd"}, WordDictOptions{})
+		word, pos, ok := d.Find("abcdx")
+		So(ok, ShouldBeTrue)
+		So(word, ShouldEqual, "cd")
+		So(pos, ShouldEqual, 2)
+
+		word, pos, ok = d.Find("xabcde")
+		So(ok, ShouldBeTrue)
+		So(word, ShouldEqual, "cd")
+		So(pos, ShouldEqual, 3)
+
+		d = NewWordDict([]string{"bcd", "abcdef"}, WordDictOptions{})
+		word, pos, _ = d.Find("zabcdz")
+		So(word, ShouldEqual, "bcd")
+		So(pos, ShouldEqual, 2)
+	})
+
+	Convey("测试按字符计算位置及归一化", t, func() {
+		d := NewWordDict([]string{"坏人"}, WordDictOptions{})
+		word, pos, ok := d.Find("他是坏人")
+		So(ok, ShouldBeTrue)
+		So(word, ShouldEqual, "坏人")
+		So(pos, ShouldEqual, 2)
+
+		_, _, ok = NewWordDict([]string{"abc"}, WordDictOptions{}).Find("ＡＢＣ")
+		So(ok, ShouldBeFalse)
+		_, _, ok = NewWordDict([]string{"abc"}, WordDictOptions{FoldWidth: true}).Find("ＡＢＣ")
+		So(ok, ShouldBeFalse)
+		word, _, ok = NewWordDict([]string{"Abc"}, WordDictOptions{FoldWidth: true, FoldCase: true}).Find("xＡＢＣ")
+		So(ok, ShouldBeTrue)
+		So(word, ShouldEqual, "Abc")
+	})
+
+	Convey("测试敏感词规则", t, func() {
+		filename := t.TempDir() + "/words.txt"
+		So(os.WriteFile(filename, []byte("# 注释\n坏人\n\nbad\n"), 0644), ShouldBeNil)
+		d, err := LoadWordDict(filename, WordDictOptions{FoldCase: true})
+		So(err, ShouldBeNil)
+		So(d.Len(), ShouldEqual, 2)
+
+		v := NewValidator()
+		v.NewParam("title").MustNotContainWords(d)
+		v.NewParam("tags").MustSeparator(",", reflect.String).MustNotContainWords(d)
+		So(v.Compile(), ShouldBeNil)
+		So(Validate(url.Values{"title": {"好人"}, "tags": {"a,b"}}, v), ShouldBeNil)
+
+		err = Validate(url.Values{"title": {"一个BAD人"}}, v)
+		So(errors.Is(err, CodeMustNotContainWords), ShouldBeTrue)
+		So(err.(*ParamsError).Args, ShouldResemble, []interface{}{"bad", 2})
+		So(errors.Is(Validate(url.Values{"tags": {"a,坏人"}}, v), CodeMustNotContainWords), ShouldBeTrue)
+	})
+}
+
+func Test_Transform(t *testing.T) {
+	Convey("测试参数预处理", t, func() {
+		v := NewValidator()
+		v.NewParam("name").Trim().ToLower()
+		v.NewParam("code").ToUpper()
+		v.NewParam("title").CollapseSpace()
+		v.NewParam("full").ToHalfWidth()
+		v.NewParam("text").StripControl()
+		v.NewParam("slug").Trim().Transform("dash", func(s string) string {
+			return strings.ReplaceAll(s, " ", "-")
+		})
+		So(v.Compile(), ShouldBeNil)
+
+		So(Validate(url.Values{"name": {"  Bob "}, "code": {"ab1"}, "title": {" a \t b\n c "}, "full": {"ＡＢＣ１２３　x"},
+			"text": {"a\u200bb\x00c"}, "slug": {" hello world "}}, v), ShouldBeNil)
+		So(v.valueMap["name"], ShouldEqual, "bob")
+		So(v.valueMap["code"], ShouldEqual, "AB1")
+		So(v.valueMap["title"], ShouldEqual, "a b c")
+		So(v.valueMap["full"], ShouldEqual, "ABC123 x")
+		So(v.valueMap["text"], ShouldEqual, "abc")
+		So(v.valueMap["slug"], ShouldEqual, "hello-world")
+	})
+
+	Convey("测试预处理按声明顺序执行", t, func() {
+		v := NewValidator()
+		v.NewParam("a").Transform("dash", func(s string) string {
+			return strings.ReplaceAll(s, " ", "-")
+		}).Trim()
+		v.NewParam("b").Trim().Transform("dash", func(s string) string {
+			return strings.ReplaceAll(s, " ", "-")
+		})
+		So(Validate(url.Values{"a": {" x "}, "b": {" x "}}, v), ShouldBeNil)
+		So(v.valueMap["a"], ShouldEqual, "-x-")
+		So(v.valueMap["b"], ShouldEqual, "x")
+		So(v.ApiParams["a"].TransformDoc(), ShouldEqual, "dash, trim")
+		So(v.ApiParams["b"].TransformDoc(), ShouldEqual, "trim, dash")
+	})
+
+	Convey("测试预处理在类型转换和必传校验之前执行", t, func() {
+		v := NewValidator()
+		v.NewParam("page").Trim().MustInt().MustMin(1)
+		v.NewParam("name").Require(true).Trim()
+		So(Validate(url.Values{"page": {" 2 "}, "name": {"bob"}}, v), ShouldBeNil)
+		So(v.valueMap["page"], ShouldEqual, 2)
+		So(errors.Is(Validate(url.Values{"page": {" 0 "}, "name": {"bob"}}, v), CodeMustMin), ShouldBeTrue)
+		So(errors.Is(Validate(url.Values{"name": {"   "}}, v), CodeRequireNotNull), ShouldBeTrue)
+
+		err := UrlValidator(map[string]string{"page": " 3", "name": " bob "}, v)
+		So(err, ShouldBeNil)
+		So(v.valueMap["page"], ShouldEqual, 3)
+		So(v.valueMap["name"], ShouldEqual, "bob")
+	})
+}
+
+func Test_Presence(t *testing.T) {
+	Convey("测试空值默认跳过规则，CheckEmpty时执行规则", t, func() {
+		v := NewValidator()
+		v.NewParam("code").Require(true).AllowEmpty().CheckEmpty().MustLength(3)
+		v.NewParam("page").MustInt()
+		v.NewParam("sort", "asc").MustValues([]interface{}{"asc", "desc"})
+		So(v.Compile(), ShouldBeNil)
+
+		So(errors.Is(Validate(url.Values{"code": {""}}, v), CodeMustLength), ShouldBeTrue)
+		So(Validate(url.Values{"code": {"abc"}, "page": {""}, "sort": {""}, "other": {""}}, v), ShouldBeNil)
+		So(v.Presence("page"), ShouldEqual, PresenceEmpty)
+		So(v.Presence("sort"), ShouldEqual, PresenceEmpty)
+		var dst struct {
+			Sort string `valid:"sort"`
+		}
+		So(v.ValuesToStruct(&dst), ShouldBeNil)
+		So(dst.Sort, ShouldEqual, "")
+
+		So(Validate(url.Values{"code": {"abc"}}, v), ShouldBeNil)
+		So(v.ValuesToStruct(&dst), ShouldBeNil)
+		So(dst.Sort, ShouldEqual, "asc")
+	})
+
+	Convey("测试null和NotEmptyIfPresent", t, func() {
+		v := NewValidator().SetNullToken("null")
+		v.NewParam("page", 1).MustInt()
+		v.NewParam("name").NotEmptyIfPresent()
+		So(Validate(url.Values{"page": {"null"}}, v), ShouldBeNil)
+		So(v.Presence("page"), ShouldEqual, PresenceNull)
+		_, ok := v.valueMap["page"]
+		So(ok, ShouldBeFalse)
+		So(errors.Is(Validate(url.Values{"name": {""}}, v), CodeMustNotEmpty), ShouldBeTrue)
+		So(errors.Is(Validate(url.Values{"name": {"null"}}, v), CodeMustNotEmpty), ShouldBeTrue)
+	})
+
+	Convey("测试重复校验时清空上一次的结果", t, func() {
+		v := NewValidator()
+		v.NewParam("page").MustInt()
+		v.NewParam("site").MustURL()
+		So(Validate(url.Values{"page": {"2"}, "site": {"https://example.com"}}, v), ShouldBeNil)
+		So(v.Presence("page"), ShouldEqual, PresenceValue)
+
+		So(Validate(url.Values{}, v), ShouldBeNil)
+		_, ok := v.valueMap["page"]
+		So(ok, ShouldBeFalse)
+		So(v.Presence("page"), ShouldEqual, PresenceAbsent)
+		var dst struct {
+			Page int      `valid:"page"`
+			Site *url.URL `valid:"site"`
+		}
+		So(v.ValuesToStruct(&dst), ShouldBeNil)
+		So(dst.Page, ShouldEqual, 0)
+		So(dst.Site, ShouldBeNil)
+
+		So(UrlValidator(map[string]string{"page": "3"}, v), ShouldBeNil)
+		So(UrlValidator(map[string]string{}, v), ShouldBeNil)
+		_, ok = v.valueMap["page"]
+		So(ok, ShouldBeFalse)
+	})
+}
+
+func Test_Profile(t *testing.T) {
+	Convey("测试profile的必须、可选和禁止参数", t, func() {
+		v := NewValidator()
+		v.NewParam("id").Require(true).MustInt()
+		v.NewParam("name").Require(true)
+		v.NewParam("email").MustEmail()
+		v.Profile("create").Forbid("id").Require("email")
+		v.Profile("update").Optional("*").Require("id")
+		v.Profile("patch").Optional("name").Forbid("email")
+		So(v.Compile(), ShouldBeNil)
+
+		create := v.Profile("create").Validator()
+		So(Validate(url.Values{"name": {"bob"}, "email": {"a@b.cn"}}, create), ShouldBeNil)
+		So(errors.Is(Validate(url.Values{"id": {"1"}, "name": {"bob"}, "email": {"a@b.cn"}}, create), CodeForbiddenParam), ShouldBeTrue)
+		So(errors.Is(Validate(url.Values{"name": {"bob"}}, create), CodeRequireParam), ShouldBeTrue)
+
+		update := v.Profile("update").Validator()
+		So(Validate(url.Values{"id": {"1"}}, update), ShouldBeNil)
+		So(errors.Is(Validate(url.Values{"name": {"bob"}}, update), CodeRequireParam), ShouldBeTrue)
+
+		patch := v.Profile("patch").Validator()
+		So(Validate(url.Values{"id": {"1"}}, patch), ShouldBeNil)
+		So(errors.Is(Validate(url.Values{"id": {"1"}, "email": {"a@b.cn"}}, patch), CodeForbiddenParam), ShouldBeTrue)
+
+		So(errors.Is(Validate(url.Values{"name": {"bob"}}, v), CodeRequireParam), ShouldBeTrue)
+		So(Validate(url.Values{"id": {"1"}, "name": {"bob"}, "email": {"a@b.cn"}}, v), ShouldBeNil)
+		So(v.ApiParams["id"].Require, ShouldBeTrue)
+		So(v.ApiParams["email"].Require, ShouldBeFalse)
+	})
+
+	Convey("测试profile文档", t, func() {
+		v := NewValidator()
+		v.NewParam("id").Require(true)
+		v.NewParam("name")
+		v.Profile("update").Optional("*").Require("id")
+		v.Profile("create").Forbid("id").Require("name")
+		So(v.ProfileDocs(), ShouldResemble, []ProfileDoc{
+			{Name: "create", Params: []ProfileParamDoc{{Name: "id", Status: "禁止"}, {Name: "name", Status: "必须"}}},
+			{Name: "update", Params: []ProfileParamDoc{{Name: "id", Status: "必须"}, {Name: "name", Status: "可选"}}},
+		})
+		So(NewValidator().ProfileDocs(), ShouldBeNil)
+	})
+}
+
+func Test_Include(t *testing.T) {
+	Convey("测试合并参数时加上前缀", t, func() {
+		rangeGroup := NewValidator()
+		rangeGroup.Name = "range"
+		rangeGroup.NewParam("start").Require(true).MustInt()
+		rangeGroup.NewParam("end").MustInt().MustLargeThan("start")
+
+		v := NewValidator()
+		v.NewParam("start").MustInt()
+		So(v.Include(rangeGroup, "price_"), ShouldBeNil)
+		So(v.Compile(), ShouldBeNil)
+		So(v.ApiParams["price_start"].Require, ShouldBeTrue)
+		So(v.ApiParams["price_end"].Source, ShouldEqual, "range")
+		So(v.ApiParams["price_end"].RuleDoc(), ShouldEqual, ">参数price_start")
+		So(v.ApiParams["start"].Require, ShouldBeFalse)
+
+		So(Validate(url.Values{"price_start": {"1"}, "price_end": {"2"}, "start": {"5"}}, v), ShouldBeNil)
+		So(Validate(url.Values{"price_start": {"3"}, "price_end": {"2"}, "start": {"1"}}, v), ShouldNotBeNil)
+		So(errors.Is(Validate(url.Values{"start": {"1"}}, v), CodeRequireParam), ShouldBeTrue)
+		So(rangeGroup.ApiParams["end"].RuleDoc(), ShouldEqual, ">参数start")
+	})
+
+	Convey("测试合并参数冲突时不做修改", t, func() {
+		group := NewValidator()
+		group.NewParam("page").MustInt()
+		group.NewParam("size").Require(true).MustInt()
+
+		v := NewValidator()
+		v.NewParam("size").MustInt()
+		err := v.Include(group, "")
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldContainSubstring, "size")
+		_, ok := v.ApiParams["page"]
+		So(ok, ShouldBeFalse)
+		So(v.ApiParams["size"].Require, ShouldBeFalse)
+		So(v.Include(group, "q_"), ShouldBeNil)
+	})
+
+	Convey("测试注册参数组", t, func() {
+		pagination := NewValidator()
+		pagination.NewParam("page").MustInt().MustMin(1)
+		RegisterParamGroup("test_pagination", pagination)
+		So(pagination.Name, ShouldEqual, "")
+		pagination.NewParam("size").MustInt()
+
+		v := NewValidator()
+		So(v.UseGroup("test_pagination", ""), ShouldBeNil)
+		So(v.ApiParams["page"].Source, ShouldEqual, "test_pagination")
+		_, ok := v.ApiParams["size"]
+		So(ok, ShouldBeFalse)
+		So(errors.Is(Validate(url.Values{"page": {"0"}}, v), CodeMustMin), ShouldBeTrue)
+		So(v.UseGroup("test_missing", ""), ShouldNotBeNil)
+	})
+}
+
+func Test_Clone(t *testing.T) {
+	Convey("测试深拷贝不影响原validator", t, func() {
+		base := NewValidator()
+		base.NewParam("page").MustInt().MustMin(1)
+
+		cloned := base.Clone()
+		cloned.NewParam("extra").Require(true)
+		cloned.NewParam("page").MustInt().MustMax(10)
+
+		_, ok := base.ApiParams["extra"]
+		So(ok, ShouldBeFalse)
+		So(base.requireParams, ShouldHaveLength, 0)
+		So(base.ruleMap["page"], ShouldHaveLength, 2)
+
+		So(Validate(url.Values{"page": {"20"}}, base), ShouldBeNil)
+		So(Validate(url.Values{"page": {"20"}, "extra": {"1"}}, cloned), ShouldNotBeNil)
+		So(Validate(url.Values{"page": {"5"}}, cloned), ShouldNotBeNil)
+	})
+
+	Convey("测试写时复制派生不影响原validator", t, func() {
+		base := NewValidator()
+		base.NewParam("page").MustInt().MustMin(1)
+
+		derived := base.Derive()
+		So(derived.ApiParams["page"], ShouldEqual, base.ApiParams["page"])
+
+		derived.NewParam("extra").Require(true)
+		_, ok := base.ApiParams["extra"]
+		So(ok, ShouldBeFalse)
+		So(Validate(url.Values{"page": {"1"}}, base), ShouldBeNil)
+		So(Validate(url.Values{"page": {"1"}}, derived), ShouldNotBeNil)
+
+		other := base.Derive()
+		base.NewParam("size").Require(true)
+		_, ok = other.ApiParams["size"]
+		So(ok, ShouldBeFalse)
+		So(Validate(url.Values{"page": {"1"}}, other), ShouldBeNil)
+	})
+}
+
+func Test_ProfileDerive(t *testing.T) {
+	Convey("测试派生后修改profile互不影响", t, func() {
+		base := NewValidator()
+		base.NewParam("id").Require(true)
+		base.NewParam("name").Require(true)
+		p := base.Profile("update").Require("id")
+		d := base.Derive()
+		p.Optional("id")
+
+		So(errors.Is(Validate(url.Values{"name": {"bob"}}, d.Profile("update").Validator()), CodeRequireParam), ShouldBeTrue)
+		So(Validate(url.Values{"name": {"bob"}}, base.Profile("update").Validator()), ShouldBeNil)
+
+		d.Profile("update").Forbid("name")
+		So(Validate(url.Values{"name": {"bob"}}, base.Profile("update").Validator()), ShouldBeNil)
+		So(errors.Is(Validate(url.Values{"id": {"1"}, "name": {"bob"}}, d.Profile("update").Validator()), CodeForbiddenParam), ShouldBeTrue)
+
+		c := base.Clone()
+		p.Forbid("name")
+		So(Validate(url.Values{"id": {"1"}, "name": {"bob"}}, c.Profile("update").Validator()), ShouldBeNil)
+	})
+}
+
+func Test_CompileErrors(t *testing.T) {
+	Convey("测试Compile拒绝无效的声明", t, func() {
+		for _, c := range []struct {
+			name    string
+			declare func(v *Validator)
+			errText string
+		}{
+			{"字符串规则用于整数参数", func(v *Validator) { v.NewParam("n").MustInt().MustLength(3) }, "MustLength requires a string param"},
+			{"引用未声明的参数", func(v *Validator) { v.NewParam("end").MustInt().MustLargeThan("start") }, "undeclared param start"},
+			{"边界不可能同时满足", func(v *Validator) { v.NewParam("n").MustInt().MustMin(10).MustMax(5) }, "can never both be satisfied"},
+			{"长度范围最小值大于最大值", func(v *Validator) { v.NewParam("s").MustLengthRange(5, 3) }, "MustLengthRange min 5 is greater than max 3"},
+			{"默认值无法转换", func(v *Validator) { v.NewParam("n", "x").MustInt() }, "default"},
+			{"默认值不满足规则", func(v *Validator) { v.NewParam("n", "0").MustInt().MustMin(1) }, "default value"},
+			{"无效的时间格式", func(v *Validator) { v.NewParam("t").MustTimeLayout("abc") }, "contains no layout element"},
+			{"不支持的列表元素类型", func(v *Validator) { v.NewParam("ids").MustSeparator(",", reflect.Uint) }, "does not support element type uint"},
+			{"未注册的自定义类型", func(v *Validator) { v.NewParam("x").MustType("test_unregistered") }, "type test_unregistered is not registered"},
+			{"边界不可比较", func(v *Validator) { v.NewParam("n").MustInt().MustMinValue("1") }, "is not a number or time.Time"},
+			{"数值边界用于字符串参数", func(v *Validator) { v.NewParam("s").MustMinValue(1) }, "requires a numeric param"},
+			{"时间边界缺少时间格式", func(v *Validator) { v.NewParam("t").MustMinValue(time.Now()) }, "requires MustTimeLayout"},
+			{"时间边界用于整数参数", func(v *Validator) { v.NewParam("t").MustInt().MustMinValue(time.Now()) }, "requires a string param"},
+			{"可选值类型不匹配", func(v *Validator) { v.NewParam("n").MustValues([]interface{}{1, "x"}).MustInt() }, "MustValues value x(string) is not int"},
+			{"排除值类型不匹配", func(v *Validator) { v.NewParam("n").MustInt().MustNotValues([]interface{}{1.5}) }, "MustNotValues value 1.5(float64) is not int"},
+			{"声明第二种格式", func(v *Validator) { v.NewParam("h").MustEmail().MustHostname() }, "conflicts with format email"},
+			{"格式用于整数参数", func(v *Validator) { v.NewParam("n").MustEmail().MustInt() }, "requires a string param"},
+		} {
+			v := NewValidator()
+			c.declare(v)
+			err := v.Compile()
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, c.errText)
+		}
+	})
+
+	Convey("测试Compile接受与声明顺序无关的声明", t, func() {
+		for _, declare := range []func(v *Validator){
+			func(v *Validator) { v.NewParam("t").MustMinValue(time.Now()).MustTimeLayout("2006-01-02") },
+			func(v *Validator) { v.NewParam("t").MustTimeLayout("2006-01-02").MustMinValue(time.Now()) },
+			func(v *Validator) { v.NewParam("n").MustValues([]interface{}{"1", "2"}).MustInt() },
+			func(v *Validator) { v.NewParam("n").MustInt().MustValues([]interface{}{"1", "2"}) },
+			func(v *Validator) { v.NewParam("n").MustInt().MustMin(1).MustMax(1) },
+			func(v *Validator) { v.NewParam("start").MustInt(); v.NewParam("end").MustInt().MustLargeThan("start") },
+		} {
+			v := NewValidator()
+			declare(v)
+			So(v.Compile(), ShouldBeNil)
+		}
+	})
+}
+
+func Test_Defaults(t *testing.T) {
+	Convey("测试默认值按声明的类型转换", t, func() {
+		v := NewValidator()
+		v.NewParam("page", "5").MustInt()
+		v.NewParam("ratio", 1).MustFloat64()
+		v.NewParam("ids", "1,2").MustSeparator(",", reflect.Int64)
+		v.NewParam("active", "true").MustBool()
+		v.NewParam("name", "guest")
+		So(v.Compile(), ShouldBeNil)
+		So(v.ApiParams["page"].Default, ShouldEqual, 5)
+		So(v.ApiParams["page"].HasDefault, ShouldBeTrue)
+		So(v.ApiParams["ratio"].Default, ShouldEqual, 1.0)
+		So(v.ApiParams["ids"].Default, ShouldResemble, []interface{}{int64(1), int64(2)})
+		So(v.ApiParams["page"].DefaultDoc(), ShouldEqual, "5")
+		So(v.ApiParams["ids"].DefaultDoc(), ShouldEqual, "[1 2]")
+
+		So(Validate(url.Values{}, v), ShouldBeNil)
+		So(v.Int("page"), ShouldEqual, 5)
+		So(v.Float64("ratio"), ShouldEqual, 1.0)
+		So(v.Bool("active"), ShouldBeTrue)
+		So(v.String("name"), ShouldEqual, "guest")
+		var dst struct {
+			Page int     `valid:"page"`
+			IDs  []int64 `valid:"ids"`
+		}
+		So(v.ValuesToStruct(&dst), ShouldBeNil)
+		So(dst.Page, ShouldEqual, 5)
+		So(dst.IDs, ShouldResemble, []int64{1, 2})
+
+		So(Validate(url.Values{"page": {"7"}}, v), ShouldBeNil)
+		So(v.Int("page"), ShouldEqual, 7)
+		So(v.Presence("page"), ShouldEqual, PresenceValue)
+	})
+
+	Convey("测试默认值文档", t, func() {
+		v := NewValidator()
+		v.NewParam("page", 1).MustInt()
+		v.NewParam("size").MustInt()
+		v.NewParam("end").MustInt().DefaultFunc(func(g ValueGetter) interface{} { return 1 })
+		So(v.ApiParams["page"].DefaultDoc(), ShouldEqual, "1")
+		So(v.ApiParams["size"].DefaultDoc(), ShouldEqual, "")
+		So(v.ApiParams["end"].DefaultDoc(), ShouldEqual, "动态")
+	})
+
+	Convey("测试无效的默认值", t, func() {
+		v := NewValidator()
+		v.NewParam("page", "x").MustInt()
+		So(v.Compile(), ShouldNotBeNil)
+		So(v.ApiParams["page"].Default, ShouldEqual, "x")
+
+		v = NewValidator()
+		v.NewParam("email", "bad").MustEmail()
+		So(v.Compile(), ShouldNotBeNil)
+
+		v = NewValidator()
+		v.NewParam("size", 200).MustInt().MustMax(100)
+		So(v.Compile(), ShouldNotBeNil)
+	})
+}
+
+func Test_DefaultFunc(t *testing.T) {
+	Convey("测试动态默认值按声明顺序计算", t, func() {
+		v := NewValidator()
+		v.NewParam("page").MustInt()
+		v.NewParam("a").MustInt().DefaultFunc(func(g ValueGetter) interface{} { return g.Int("page") + 1 })
+		v.NewParam("b").MustInt().DefaultFunc(func(g ValueGetter) interface{} { return g.Int("a") * 10 })
+		v.NewParam("c").MustInt().DefaultFunc(func(g ValueGetter) interface{} { return g.Int("b") + g.Int("a") })
+		v.NewParam("a").MustInt().DefaultFunc(func(g ValueGetter) interface{} { return g.Int("page") + 2 })
+		So(v.Compile(), ShouldBeNil)
+		for i := 0; i < 20; i++ {
+			So(Validate(url.Values{"page": {"1"}}, v), ShouldBeNil)
+			So(v.Int("c"), ShouldEqual, 33)
+		}
+		So(Validate(url.Values{"page": {"1"}, "a": {"5"}}, v), ShouldBeNil)
+		So(v.Int("b"), ShouldEqual, 50)
+	})
+
+	Convey("测试引入参数组时复制动态默认值和计算参数", t, func() {
+		paging := NewValidator()
+		paging.NewParam("page", 1).MustInt()
+		paging.NewParam("size").MustInt().DefaultFunc(func(g ValueGetter) interface{} { return g.Int("page") * 10 })
+		paging.Computed("offset", reflect.Int, func(g ValueGetter) (interface{}, error) {
+			return (g.Int("page") - 1) * g.Int("size"), nil
+		})
+
+		v := NewValidator()
+		v.NewParam("page").MustInt()
+		So(v.Include(paging, "sub_"), ShouldBeNil)
+		So(Validate(url.Values{"page": {"9"}, "sub_page": {"3"}}, v), ShouldBeNil)
+		So(v.Int("sub_size"), ShouldEqual, 30)
+		So(v.Int("sub_offset"), ShouldEqual, 60)
+		var dst struct {
+			Offset int `valid:"sub_offset"`
+		}
+		So(v.ValuesToStruct(&dst), ShouldBeNil)
+		So(dst.Offset, ShouldEqual, 60)
+
+		So(Validate(url.Values{}, paging), ShouldBeNil)
+		So(paging.Int("offset"), ShouldEqual, 0)
+		So(paging.Int("size"), ShouldEqual, 10)
+
+		v = NewValidator()
+		v.Computed("offset", reflect.Int, func(g ValueGetter) (interface{}, error) { return 0, nil })
+		err := v.Include(paging, "")
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldContainSubstring, "offset")
+	})
+}
+
+type BindPaging struct {
+	Page *int  `valid:"page"`
+	Size uint8 `valid:"size"`
+}
+
+type BindNode struct {
+	Name  string `valid:"name"`
+	Next  *BindNode
+	Child struct {
+		Name string `valid:"name"`
+		Next *BindNode
+	}
+}
+
+func Test_ValuesToStruct(t *testing.T) {
+	Convey("测试嵌套、嵌入及指针字段绑定", t, func() {
+		v := NewValidator()
+		v.NewParam("page").MustInt()
+		v.NewParam("size", 20).MustInt()
+		v.NewParam("ids").MustSeparator(",", reflect.Int64)
+		So(Validate(url.Values{"page": {"3"}, "ids": {"1,2"}}, v), ShouldBeNil)
+
+		var dst struct {
+			*BindPaging
+			Filter struct {
+				IDs []int64 `valid:"ids"`
+			}
+		}
+		So(v.ValuesToStruct(&dst), ShouldBeNil)
+		So(*dst.Page, ShouldEqual, 3)
+		So(dst.Size, ShouldEqual, uint8(20))
+		So(dst.Filter.IDs, ShouldResemble, []int64{1, 2})
+	})
+
+	Convey("测试类型不匹配时返回错误", t, func() {
+		v := NewValidator()
+		v.NewParam("page").MustInt()
+		So(Validate(url.Values{"page": {"300"}}, v), ShouldBeNil)
+
+		var wrong struct {
+			Page bool `valid:"page"`
+		}
+		So(v.ValuesToStruct(&wrong), ShouldNotBeNil)
+		var overflow struct {
+			Page int8 `valid:"page"`
+		}
+		So(v.ValuesToStruct(&overflow), ShouldNotBeNil)
+	})
+
+	Convey("测试自引用的结构体", t, func() {
+		v := NewValidator()
+		v.NewParam("name").Require(true)
+		So(Validate(url.Values{"name": {"root"}}, v), ShouldBeNil)
+
+		var dst BindNode
+		So(v.ValuesToStruct(&dst), ShouldBeNil)
+		So(dst.Name, ShouldEqual, "root")
+		So(dst.Next, ShouldBeNil)
+		So(dst.Child.Name, ShouldEqual, "root")
+		So(dst.Child.Next, ShouldBeNil)
+	})
+}
+
+//go:generate go run ./cmd/validgen -type=GenRequest -qualifier= -output=validator_gen_test.go validator_test.go
+
+type GenLevel int8
+
+type GenPaging struct {
+	Page *int  `valid:"page" validate:"default=1,min=1"`
+	Size uint8 `valid:"size" validate:"default=20,max=100"`
+}
+
+type GenNode struct {
+	Label string `valid:"node"`
+	Next  *GenNode
+	Link  *GenLink
+}
+
+type GenLink struct {
+	Target string `valid:"target"`
+	Back   *GenNode
+}
+
+type GenRequest struct {
+	*GenPaging
+	Keyword string    `valid:"keyword" validate:"trim,label=关键字"`
+	Level   GenLevel  `valid:"level"`
+	IDs     []int64   `valid:"ids"`
+	Tags    []*string `valid:"tags"`
+	Ratio   float32   `valid:"ratio"`
+	Active  *bool     `valid:"active"`
+	Site    *url.URL  `valid:"site" validate:"url"`
+	Filter  struct {
+		Email string `valid:"email" validate:"email"`
+	}
+	Ignored string `valid:"-"`
+	Node    *GenNode
+}
+
+func Test_GeneratedBinder(t *testing.T) {
+	Convey("测试生成代码与结构体定义同步", t, func() {
+		src, err := gen.GenerateFiles([]string{"validator_test.go"}, gen.Config{Types: []string{"GenRequest"}, Command: "validgen"})
+		So(err, ShouldBeNil)
+		generated, err := os.ReadFile("validator_gen_test.go")
+		So(err, ShouldBeNil)
+		So(string(src), ShouldEqual, string(generated))
+	})
+
+	Convey("测试生成的绑定与ValuesToStruct结果一致", t, func() {
+		for _, params := range []url.Values{
+			{"page": {"2"}, "keyword": {" go "}, "level": {"3"}, "ids": {"1,2"}, "tags": {"a,b"},
+				"ratio": {"0.5"}, "active": {"true"}, "site": {"https://example.com"}, "email": {"a@b.cn"},
+				"node": {"n"}, "target": {"t"}},
+			{"active": {"null"}},
+		} {
+			v := NewGenRequestValidator()
+			v.SetNullToken("null")
+			So(Validate(params, v), ShouldBeNil)
+			var reflected, generated GenRequest
+			So(v.ValuesToStruct(&reflected), ShouldBeNil)
+			So(generated.BindFrom(v), ShouldBeNil)
+			So(generated, ShouldResemble, reflected)
+		}
+	})
+}
+
+func Test_GenerateMatchOption(t *testing.T) {
+	Convey("测试单引号包围的match正则可以包含逗号", t, func() {
+		src := "package p\n\ntype Req struct {\n\tCode string `valid:\"code\" validate:\"trim,match='^\\\\d{1,3}(,\\\\d{3})*$',label=编码\"`\n}\n"
+		f, err := parser.ParseFile(token.NewFileSet(), "req.go", src, 0)
+		So(err, ShouldBeNil)
+		out, err := gen.Generate([]*ast.File{f}, gen.Config{Types: []string{"Req"}, Command: "validgen"})
+		So(err, ShouldBeNil)
+		So(string(out), ShouldContainSubstring, `v.NewParam("code").Trim().MustMatch("^\\d{1,3}(,\\d{3})*$").Label("编码")`)
+
+		src = strings.Replace(src, "*$'", "*$", 1)
+		f, err = parser.ParseFile(token.NewFileSet(), "req.go", src, 0)
+		So(err, ShouldBeNil)
+		_, err = gen.Generate([]*ast.File{f}, gen.Config{Types: []string{"Req"}, Command: "validgen"})
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func Test_Param(t *testing.T) {
+	Convey("测试类型化参数句柄", t, func() {
+		v := NewValidator()
+		page := Int(v, "page", 1).Min(1).Max(100)
+		ids := Int64s(v, "ids", ",")
+		So(v.Compile(), ShouldBeNil)
+
+		So(Validate(url.Values{"ids": {"1,2"}}, v), ShouldBeNil)
+		So(page.Get(v), ShouldEqual, 1)
+		So(ids.Get(v), ShouldResemble, []int64{1, 2})
+
+		So(Validate(url.Values{"page": {"101"}}, v), ShouldNotBeNil)
+		So(v.ApiParams["page"].RuleDoc(), ShouldEqual, ">=1, <=100")
+	})
+}
+
+func Test_Encode(t *testing.T) {
+	Convey("测试按声明编码结构体", t, func() {
+		v := NewValidator()
+		v.NewParam("page", 1).MustInt().MustMin(1)
+		v.NewParam("ids").MustSeparator("|", reflect.Int64)
+		v.NewParam("on").MustBool()
+		v.NewParam("notify", true).MustBool()
+		v.NewParam("size").MustInt()
+		on := false
+		src := struct {
+			Page   int     `valid:"page"`
+			IDs    []int64 `valid:"ids"`
+			On     *bool   `valid:"on"`
+			Notify bool    `valid:"notify"`
+			Size   int     `valid:"size"`
+		}{Page: 2, IDs: []int64{1, 2}, On: &on, Notify: true}
+
+		values, err := v.Encode(src)
+		So(err, ShouldBeNil)
+		So(values.Encode(), ShouldEqual, "ids=1%7C2&notify=true&on=false&page=2")
+
+		src.Notify = false
+		values, err = v.Encode(src)
+		So(err, ShouldBeNil)
+		So(values.Get("notify"), ShouldEqual, "false")
+		So(values.Has("size"), ShouldBeFalse)
+
+		src.Page = 0
+		_, err = v.Encode(src)
+		So(errors.Is(err, CodeMustMin), ShouldBeTrue)
+
+		src.Page = -1
+		_, err = v.Encode(src)
+		So(err, ShouldNotBeNil)
+	})
+}
+
+type testCents int64
+
+func Test_RegisterType(t *testing.T) {
+	Convey("测试自定义类型", t, func() {
+		RegisterType("cents", func(s string) (interface{}, error) {
+			n, err := strconv.ParseInt(s, 10, 64)
+			return testCents(n), err
+		}, func(value interface{}) (string, error) {
+			return strconv.FormatInt(int64(value.(testCents)), 10), nil
+		})
+		v := NewValidator()
+		v.NewParam("price").MustType("cents")
+		v.NewParam("prices").MustSeparator(",", reflect.String).MustType("cents")
+		So(v.Compile(), ShouldBeNil)
+		So(v.ApiParams["price"].Type, ShouldEqual, "cents")
+
+		So(Validate(url.Values{"price": {"150"}, "prices": {"1,2"}}, v), ShouldBeNil)
+		var dst struct {
+			Price  testCents   `valid:"price"`
+			Prices []testCents `valid:"prices"`
+		}
+		So(v.ValuesToStruct(&dst), ShouldBeNil)
+		So(dst.Price, ShouldEqual, testCents(150))
+		So(dst.Prices, ShouldResemble, []testCents{1, 2})
+
+		values, err := v.Encode(dst)
+		So(err, ShouldBeNil)
+		So(values.Encode(), ShouldEqual, "price=150&prices=1%2C2")
+
+		So(Validate(url.Values{"price": {"1.5"}}, v), ShouldNotBeNil)
+	})
+}
+
+func Test_MustRule(t *testing.T) {
+	Convey("测试上下文规则", t, func() {
+		v := NewValidator()
+		v.NewParam("start").MustInt()
+		v.NewParam("end").MustInt().Label("结束时间").MustRule(RuleFunc(func(ctx context.Context, value interface{}, fields Fields) Outcome {
+			if value.(int) <= fields.Int("start") {
+				return Fail("end_before_start", "结束时间必须晚于开始时间", fields.Int("start"))
+			}
+			return Pass()
+		}))
+		v.NewParam("name").MustRule(AdaptFunc(func(key string, value interface{}, params url.Values, cus bool, args ...interface{}) error {
+			if value.(string) == args[0] {
+				return NewParamsError(key, value).CustomErrorText("name不能为" + params.Get(key))
+			}
+			return nil
+		}, "admin"))
+
+		So(Validate(url.Values{"start": {"1"}, "end": {"2"}, "name": {"bob"}}, v), ShouldBeNil)
+
+		err := Validate(url.Values{"start": {"5"}, "end": {"3"}}, v)
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldEqual, "结束时间必须晚于开始时间")
+		So(errors.Is(err, ErrorCode("end_before_start")), ShouldBeTrue)
+
+		err = Validate(url.Values{"name": {"admin"}}, v)
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldEqual, "name不能为admin")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		So(ValidateContext(ctx, url.Values{"name": {"bob"}}, v), ShouldEqual, context.Canceled)
+	})
+}
+
+func Test_BoundedParam(t *testing.T) {
+	Convey("测试数值和时间参数句柄的边界规则", t, func() {
+		v := NewValidator()
+		size := Int(v, "size", 20).Require().Label("每页数量").GreaterThan(0).LessThan(101)
+		ratio := Float64(v, "ratio").Values(0.5, 1).Min(0.5)
+		start := Time(v, "start", "2006-01-02").Min(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+		So(v.Compile(), ShouldBeNil)
+		So(v.ApiParams["size"].RuleDoc(), ShouldEqual, ">0, <101")
+
+		So(Validate(url.Values{"size": {"100"}, "ratio": {"1"}, "start": {"2021-05-01"}}, v), ShouldBeNil)
+		So(size.Get(v), ShouldEqual, 100)
+		So(ratio.Get(v), ShouldEqual, 1.0)
+		So(start.Get(v).Year(), ShouldEqual, 2021)
+
+		err := Validate(url.Values{"size": {"101"}}, v)
+		So(errors.Is(err, CodeMustLessThanValue), ShouldBeTrue)
+		So(err.(*ParamsError).Label, ShouldEqual, "每页数量")
+		So(errors.Is(Validate(url.Values{"size": {"1"}, "start": {"2019-12-31"}}, v), CodeMustMin), ShouldBeTrue)
+	})
+}
+
+type testPoint struct {
+	X, Y int
+	Tags []string
+}
+
+func Test_CustomTypeOrder(t *testing.T) {
+	Convey("测试MustType与MustSeparator的声明顺序无关", t, func() {
+		RegisterType("test_cents", func(s string) (interface{}, error) {
+			n, err := strconv.ParseInt(s, 10, 64)
+			return testCents(n), err
+		}, nil)
+		v := NewValidator()
+		v.NewParam("a").MustType("test_cents").MustSeparator(",", reflect.String)
+		v.NewParam("b").MustSeparator(",", reflect.String).MustType("test_cents")
+		So(v.Compile(), ShouldBeNil)
+		So(v.ApiParams["a"].Type, ShouldEqual, "[]test_cents")
+		So(v.ApiParams["b"].Type, ShouldEqual, "[]test_cents")
+
+		So(Validate(url.Values{"a": {"1,2"}, "b": {"3"}}, v), ShouldBeNil)
+		var dst struct {
+			A []testCents `valid:"a"`
+			B []testCents `valid:"b"`
+		}
+		So(v.ValuesToStruct(&dst), ShouldBeNil)
+		So(dst.A, ShouldResemble, []testCents{1, 2})
+		So(dst.B, ShouldResemble, []testCents{3})
+		So(Validate(url.Values{"a": {"1,x"}}, v), ShouldNotBeNil)
+	})
+
+	Convey("测试不可比较的自定义类型使用MustValues", t, func() {
+		RegisterType("test_point", func(s string) (interface{}, error) {
+			x, y, _ := strings.Cut(s, ":")
+			px, err := strconv.Atoi(x)
+			if err != nil {
+				return nil, err
+			}
+			py, err := strconv.Atoi(y)
+			return testPoint{X: px, Y: py, Tags: []string{}}, err
+		}, nil)
+		v := NewValidator()
+		v.NewParam("p").MustType("test_point").MustValues([]interface{}{testPoint{X: 1, Y: 2, Tags: []string{}}})
+		v.NewParam("q").MustType("test_point").MustNotValues([]interface{}{testPoint{X: 0, Y: 0, Tags: []string{}}})
+		So(func() { Validate(url.Values{"p": {"1:2"}, "q": {"1:1"}}, v) }, ShouldNotPanic)
+		So(Validate(url.Values{"p": {"1:2"}, "q": {"1:1"}}, v), ShouldBeNil)
+		So(errors.Is(Validate(url.Values{"p": {"2:2"}}, v), CodeMustValues), ShouldBeTrue)
+		So(errors.Is(Validate(url.Values{"q": {"0:0"}}, v), CodeMustNotValues), ShouldBeTrue)
 	})
 }
@@ -0,0 +1,37 @@
+package validator
+
+//校验后参数的值，类型为声明的类型，未传值时为默认值
+func (v *Validator) Value(paramName string) (interface{}, bool) {
+	value, ok := v.valueMap[paramName]
+	return value, ok
+}
+
+func (v *Validator) Int(paramName string) int {
+	value, _ := v.valueMap[paramName].(int)
+	return value
+}
+
+func (v *Validator) Int64(paramName string) int64 {
+	value, _ := v.valueMap[paramName].(int64)
+	return value
+}
+
+func (v *Validator) Float64(paramName string) float64 {
+	value, _ := v.valueMap[paramName].(float64)
+	return value
+}
+
+func (v *Validator) Bool(paramName string) bool {
+	value, _ := v.valueMap[paramName].(bool)
+	return value
+}
+
+func (v *Validator) String(paramName string) string {
+	value, _ := v.valueMap[paramName].(string)
+	return value
+}
+
+func (v *Validator) Slice(paramName string) []interface{} {
+	value, _ := v.valueMap[paramName].([]interface{})
+	return value
+}
@@ -0,0 +1,201 @@
+package validator
+
+import (
+	"bufio"
+	"net/url"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+//敏感词词典，使用Aho-Corasick多模式匹配，构建后只读，可在多个goroutine间共享
+type WordDict struct {
+	nodes     []wordNode
+	foldWidth bool
+	foldCase  bool
+	size      int
+	dict      []string
+}
+
+type wordNode struct {
+	next map[rune]int
+	fail int
+	//以该节点结尾的最长词，-1表示无
+	word int
+	//沿fail链可匹配到的最长词
+	output int
+}
+
+type WordDictOptions struct {
+	//全角字符转为半角后匹配
+	FoldWidth bool
+	//忽略大小写
+	FoldCase bool
+}
+
+func NewWordDict(words []string, opts WordDictOptions) *WordDict {
+	d := &WordDict{
+		foldWidth: opts.FoldWidth,
+		foldCase:  opts.FoldCase,
+	}
+	d.nodes = append(d.nodes, wordNode{word: -1, output: -1})
+	var dict []string
+	for _, w := range words {
+		if w == "" {
+			continue
+		}
+		cur := 0
+		for _, c := range w {
+			c = d.normalize(c)
+			next, ok := d.nodes[cur].next[c]
+			if !ok {
+				next = len(d.nodes)
+				d.nodes = append(d.nodes, wordNode{word: -1, output: -1})
+				if d.nodes[cur].next == nil {
+					d.nodes[cur].next = make(map[rune]int)
+				}
+				d.nodes[cur].next[c] = next
+			}
+			cur = next
+		}
+		if d.nodes[cur].word < 0 {
+			d.nodes[cur].word = len(dict)
+			dict = append(dict, w)
+		}
+	}
+	d.size = len(dict)
+	d.build(dict)
+	return d
+}
+
+//从文件加载词典，每行一个词，#开头的行为注释
+func LoadWordDict(filename string, opts WordDictOptions) (*WordDict, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var words []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return NewWordDict(words, opts), nil
+}
+
+//词典中的词数
+func (d *WordDict) Len() int {
+	return d.size
+}
+
+func (d *WordDict) build(dict []string) {
+	wordLen := make([]int, len(dict))
+	for i, w := range dict {
+		wordLen[i] = utf8.RuneCountInString(w)
+	}
+	d.dict = dict
+	queue := []int{}
+	for _, child := range d.nodes[0].next {
+		d.nodes[child].fail = 0
+		d.nodes[child].output = d.nodes[child].word
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for c, child := range d.nodes[cur].next {
+			fail := d.nodes[cur].fail
+			for {
+				if next, ok := d.nodes[fail].next[c]; ok {
+					d.nodes[child].fail = next
+					break
+				}
+				if fail == 0 {
+					d.nodes[child].fail = 0
+					break
+				}
+				fail = d.nodes[fail].fail
+			}
+			output := d.nodes[child].word
+			if inherited := d.nodes[d.nodes[child].fail].output; inherited >= 0 && (output < 0 || wordLen[inherited] > wordLen[output]) {
+				output = inherited
+			}
+			d.nodes[child].output = output
+			queue = append(queue, child)
+		}
+	}
+}
+
+func (d *WordDict) normalize(c rune) rune {
+	if d.foldWidth {
+		c = toHalfWidth(c)
+	}
+	if d.foldCase {
+		c = unicode.ToLower(c)
+	}
+	return c
+}
+
+//查找第一个出现的敏感词，pos为其在text中从0开始的字符(rune)位置
+func (d *WordDict) Find(text string) (word string, pos int, ok bool) {
+	cur, i := 0, 0
+	for _, c := range text {
+		c = d.normalize(c)
+		for {
+			if next, ok := d.nodes[cur].next[c]; ok {
+				cur = next
+				break
+			}
+			if cur == 0 {
+				break
+			}
+			cur = d.nodes[cur].fail
+		}
+		if out := d.nodes[cur].output; out >= 0 {
+			word = d.dict[out]
+			return word, i - utf8.RuneCountInString(word) + 1, true
+		}
+		i++
+	}
+	return "", 0, false
+}
+
+//全角字符转半角
+func toHalfWidth(c rune) rune {
+	switch {
+	case c == 0x3000:
+		return ' '
+	case c >= 0xFF01 && c <= 0xFF5E:
+		return c - 0xFEE0
+	}
+	return c
+}
+
+func mustNotContainWords(k string, v interface{}, params url.Values, cus bool, args ...interface{}) error {
+	ok, err := isSlice(mustNotContainWords, k, v, params, cus, args...)
+	if ok {
+		return err
+	}
+	vString, ok := v.(string)
+	if !ok {
+		return nil
+	}
+	if word, pos, found := args[0].(*WordDict).Find(vString); found {
+		pErr := NewParamsError(k, v)
+		pErr.Args = []interface{}{word, pos}
+		return pErr.ErrMustNotContainWords(cus)
+	}
+	return nil
+}
+
+func (r *ruleSet) MustNotContainWords(dict *WordDict) RuleSet {
+	return r.appendRule("MustNotContainWords", mustNotContainWords, "不能包含敏感词", dict)
+}